@@ -10,6 +10,41 @@ if [ -f profile.out ]; then
    rm profile.out
 fi
 
+GO111MODULE=on go test -race -coverprofile=profile.out -covermode=atomic ./dashboard/...
+
+if [ -f profile.out ]; then
+   cat profile.out >> coverage.txt
+   rm profile.out
+fi
+
+GO111MODULE=on go test -race -coverprofile=profile.out -covermode=atomic ./analysis/...
+
+if [ -f profile.out ]; then
+   cat profile.out >> coverage.txt
+   rm profile.out
+fi
+
+GO111MODULE=on go test -race -coverprofile=profile.out -covermode=atomic ./folder/...
+
+if [ -f profile.out ]; then
+   cat profile.out >> coverage.txt
+   rm profile.out
+fi
+
+GO111MODULE=on go test -race -coverprofile=profile.out -covermode=atomic ./permission/...
+
+if [ -f profile.out ]; then
+   cat profile.out >> coverage.txt
+   rm profile.out
+fi
+
+GO111MODULE=on go test -race -coverprofile=profile.out -covermode=atomic ./audit/...
+
+if [ -f profile.out ]; then
+   cat profile.out >> coverage.txt
+   rm profile.out
+fi
+
 GO111MODULE=on go test -race -coverprofile=profile.out -covermode=atomic ./api -integration
 
 if [ -f profile.out ]; then
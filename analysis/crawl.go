@@ -0,0 +1,84 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+// Crawl builds the graph of the current organisation of the user.
+// The legacy and the unified alert rules are both crawled. The library panels and each kind of alert rules
+// are skipped if the version of Grafana doesn't provide them.
+func Crawl(client api.ClientInterface) (*Graph, error) {
+	graph := NewGraph()
+
+	datasources, err := client.DataSources().Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, datasource := range datasources {
+		graph.AddDatasource(datasource)
+	}
+
+	hits, err := client.Search().QueryAll(api.QueryParameterSearch{})
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range hits {
+		if hit.Type == types.SearchDFolderType {
+			graph.AddFolder(hit.UID, hit.Title)
+		}
+	}
+	for _, hit := range hits {
+		if hit.Type != types.SearchDashboardType {
+			continue
+		}
+		result, err := client.Dashboards().GetByUID(hit.UID)
+		if err != nil {
+			return nil, err
+		}
+		folderUID := result.Meta.FolderUID
+		if len(folderUID) == 0 {
+			folderUID = hit.FolderUID
+		}
+		graph.AddDashboard(folderUID, result.Dashboard)
+	}
+
+	libraryPanels, err := client.LibraryElements().SearchAll(api.QueryParameterLibraryElements{Kind: types.LibraryPanelKind})
+	if err != nil && !grafanahttp.IsNotFound(err) {
+		return nil, err
+	}
+	for _, libraryPanel := range libraryPanels {
+		graph.AddLibraryPanel(libraryPanel)
+	}
+
+	alerts, err := client.Alerts().Get(api.QueryParamAlert{})
+	if err != nil && !grafanahttp.IsNotFound(err) {
+		return nil, err
+	}
+	for _, alert := range alerts {
+		graph.AddAlertRule(alert)
+	}
+
+	rules, err := client.AlertRules().Get()
+	if err != nil && !grafanahttp.IsNotFound(err) {
+		return nil, err
+	}
+	for _, rule := range rules {
+		graph.AddUnifiedAlertRule(rule)
+	}
+	return graph, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrawl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/datasources":
+			_, _ = w.Write([]byte(`[{"id": 1, "uid": "prom-uid", "name": "Prometheus main", "type": "prometheus"}]`))
+		case "/api/search":
+			_, _ = w.Write([]byte(`[{"uid": "folder-uid", "title": "my folder", "type": "dash-folder"},
+				{"uid": "abcd", "title": "my dashboard", "type": "dash-db", "folderUid": "folder-uid"}]`))
+		case "/api/dashboards/uid/abcd":
+			_, _ = w.Write([]byte(`{"meta": {"folderUid": "folder-uid"}, "dashboard": ` + graphTestDashboard + `}`))
+		case "/api/library-elements":
+			_, _ = w.Write([]byte(`{"result": {"totalCount": 0, "elements": [], "page": 1, "perPage": 100}}`))
+		case "/api/v1/provisioning/alert-rules":
+			_, _ = w.Write([]byte(`[{"uid": "rule-uid", "title": "cpu too high", "folderUID": "folder-uid",
+				"data": [{"refId": "A", "datasourceUid": "prom-uid", "model": {}}],
+				"annotations": {"__dashboardUid__": "abcd", "__panelId__": "1"}}]`))
+		default:
+			// like Grafana 11, the legacy alerting API doesn't exist anymore
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	graph, err := Crawl(api.NewWithClient(rest))
+	assert.Nil(t, err)
+
+	rule := graph.Node(AlertRuleNode, "rule-uid")
+	if assert.NotNil(t, rule) {
+		assert.Equal(t, "cpu too high", rule.Name)
+	}
+	var dependents []string
+	for _, edge := range graph.ReferencedBy(graph.Node(DatasourceNode, "prom-uid")) {
+		dependents = append(dependents, edge.Dependent.key())
+	}
+	assert.Contains(t, dependents, "alert-rule:rule-uid")
+	assert.NotNil(t, graph.Node(DashboardNode, "abcd"))
+}
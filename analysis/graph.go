@@ -0,0 +1,396 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analysis builds the graph of the dependencies between the resources of an organisation
+// (datasources, folders, dashboards, panels, library panels and alert rules),
+// to know what references a resource and what would break if it was removed.
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+type NodeKind string
+
+const (
+	DatasourceNode   NodeKind = "datasource"
+	FolderNode       NodeKind = "folder"
+	DashboardNode    NodeKind = "dashboard"
+	PanelNode        NodeKind = "panel"
+	LibraryPanelNode NodeKind = "library-panel"
+	AlertRuleNode    NodeKind = "alert-rule"
+)
+
+// Node is a resource of the organisation
+type Node struct {
+	Kind NodeKind `json:"kind"`
+	// ID identifies the node among the nodes of the same kind. It's the uid of the datasources, the folders,
+	// the dashboards, the library panels and the unified alert rules, the id of the legacy alert rules
+	// and <dashboard uid>/<panel id> for the panels.
+	// An alert rule only known from the panel holding it has the id of its panel.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// DashboardUID is the dashboard holding the node when it's a panel
+	DashboardUID string `json:"dashboardUid,omitempty"`
+	// Missing is true when the node is referenced but doesn't exist, like a datasource deleted but still used
+	Missing bool `json:"missing,omitempty"`
+}
+
+func (n *Node) key() string {
+	return nodeKey(n.Kind, n.ID)
+}
+
+func (n *Node) String() string {
+	if len(n.Name) > 0 {
+		return fmt.Sprintf("%s %s (%s)", n.Kind, n.Name, n.ID)
+	}
+	return fmt.Sprintf("%s %s", n.Kind, n.ID)
+}
+
+func nodeKey(kind NodeKind, id string) string {
+	return string(kind) + ":" + id
+}
+
+// PanelNodeID returns the id of the node representing a panel
+func PanelNodeID(dashboardUID string, panelID int64) string {
+	return dashboardUID + "/" + strconv.FormatInt(panelID, 10)
+}
+
+type EdgeKind string
+
+const (
+	// UsesEdge means the dependent queries or embeds the dependency, like a panel using a datasource
+	UsesEdge EdgeKind = "uses"
+	// ContainsEdge means the dependency holds the dependent, like a folder holding a dashboard.
+	// The dependent is removed with its dependency.
+	ContainsEdge EdgeKind = "contains"
+	// AlertsEdge means the dependent is an alert rule defined on the dependency
+	AlertsEdge EdgeKind = "alerts"
+	// LinksEdge means the dependent only links to the dependency, like a unified alert rule linked to a panel.
+	// The dependent still works when the dependency is removed.
+	LinksEdge EdgeKind = "links"
+)
+
+// Edge is a dependency between two nodes
+type Edge struct {
+	Kind       EdgeKind `json:"kind"`
+	Dependent  *Node    `json:"dependent"`
+	Dependency *Node    `json:"dependency"`
+	// Path is the JSON path, in the dashboard or in the model of the library panel, of the reference
+	Path string `json:"path,omitempty"`
+}
+
+// Impact is a node that would break if another one was removed
+type Impact struct {
+	Node *Node `json:"node"`
+	// Removed is true when the node would be removed too, like a dashboard stored in a folder removed.
+	// Otherwise the node would still exist but wouldn't work anymore.
+	Removed bool `json:"removed"`
+	// Cause is the edge leading to the impact
+	Cause *Edge `json:"cause"`
+}
+
+type Graph struct {
+	nodes map[string]*Node
+	// dependents contains the edges by key of their dependency
+	dependents map[string][]*Edge
+	// dependencies contains the edges by key of their dependent
+	dependencies map[string][]*Edge
+	datasources  []*types.DataSource
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		nodes:        make(map[string]*Node),
+		dependents:   make(map[string][]*Edge),
+		dependencies: make(map[string][]*Edge),
+	}
+}
+
+// Node returns the node or nil if it doesn't exist
+func (g *Graph) Node(kind NodeKind, id string) *Node {
+	return g.nodes[nodeKey(kind, id)]
+}
+
+// Nodes returns the nodes of the given kind sorted by id
+func (g *Graph) Nodes(kind NodeKind) []*Node {
+	var result []*Node
+	for _, node := range g.nodes {
+		if node.Kind == kind {
+			result = append(result, node)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Datasource returns the node of the datasource matching the reference, or nil if no datasource matches it.
+func (g *Graph) Datasource(ref dashboard.DatasourceRef) *Node {
+	for _, datasource := range g.datasources {
+		if ref.Matches(datasource) {
+			return g.Node(DatasourceNode, datasource.UID)
+		}
+	}
+	return nil
+}
+
+// ReferencedBy returns the edges of the nodes depending directly on the node,
+// i.e. the answer to "what references this node?"
+func (g *Graph) ReferencedBy(node *Node) []*Edge {
+	return append([]*Edge{}, g.dependents[node.key()]...)
+}
+
+// DependsOn returns the edges of the nodes the node depends on directly
+func (g *Graph) DependsOn(node *Node) []*Edge {
+	return append([]*Edge{}, g.dependencies[node.key()]...)
+}
+
+// Impact returns every node that would break if the node was removed, i.e. the answer to
+// "what would break if this node was removed?". The dependencies are followed transitively:
+//   - a node using a broken or removed node is broken
+//   - a node contained by a removed node is removed, like the dashboards of a folder
+//   - an alert rule is broken when its panel is broken, or when its dashboard is removed
+//
+// A dashboard holding a broken panel isn't considered as broken, the panel node gives the dashboard.
+func (g *Graph) Impact(node *Node) []*Impact {
+	visited := map[string]bool{node.key(): true}
+	var result []*Impact
+	queue := []*Impact{{Node: node, Removed: true}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.dependents[current.Node.key()] {
+			impact := &Impact{Node: edge.Dependent, Cause: edge}
+			switch edge.Kind {
+			case ContainsEdge:
+				if !current.Removed {
+					continue
+				}
+				impact.Removed = true
+			case AlertsEdge:
+				if !current.Removed && current.Node.Kind != PanelNode {
+					continue
+				}
+			case LinksEdge:
+				continue
+			}
+			if visited[impact.Node.key()] {
+				continue
+			}
+			visited[impact.Node.key()] = true
+			result = append(result, impact)
+			queue = append(queue, impact)
+		}
+	}
+	return result
+}
+
+// AddDatasource adds a datasource to the graph. The datasources must be added before the dashboards
+// and the library panels, otherwise the references can't be resolved.
+func (g *Graph) AddDatasource(datasource *types.DataSource) *Node {
+	g.datasources = append(g.datasources, datasource)
+	return g.declare(DatasourceNode, datasource.UID, datasource.Name)
+}
+
+func (g *Graph) AddFolder(uid string, title string) *Node {
+	return g.declare(FolderNode, uid, title)
+}
+
+// AddDashboard adds a dashboard, its panels and their references to the graph.
+// An empty folderUID means the dashboard is in the General folder.
+// Panels without id don't get a node, the dashboard holds their references instead.
+func (g *Graph) AddDashboard(folderUID string, model types.DashboardModel) *Node {
+	dashboardNode := g.declare(DashboardNode, model.UID(), model.Title())
+	if len(folderUID) > 0 {
+		g.addEdge(ContainsEdge, dashboardNode, g.reference(FolderNode, folderUID), "")
+	}
+
+	panelNodes := make(map[string]*Node)
+	_ = dashboard.WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		panelID := dashboard.PanelID(panel)
+		if panelID == 0 {
+			// a panel without id can't be told apart from another one, its references are kept on the dashboard
+			return nil
+		}
+		title, _ := panel["title"].(string)
+		panelNode := g.declare(PanelNode, PanelNodeID(model.UID(), panelID), title)
+		panelNode.DashboardUID = model.UID()
+		panelNodes[path] = panelNode
+		g.addEdge(ContainsEdge, panelNode, dashboardNode, path)
+		if libraryPanel, isObject := panel["libraryPanel"].(map[string]interface{}); isObject {
+			if uid, _ := libraryPanel["uid"].(string); len(uid) > 0 {
+				g.addEdge(UsesEdge, panelNode, g.reference(LibraryPanelNode, uid), dashboard.ChildPath(path, "libraryPanel"))
+			}
+		}
+		if _, hasAlert := panel["alert"]; hasAlert {
+			alertNode := g.declare(AlertRuleNode, PanelNodeID(model.UID(), panelID), alertName(panel))
+			g.addEdge(AlertsEdge, alertNode, panelNode, dashboard.ChildPath(path, "alert"))
+			g.addEdge(AlertsEdge, alertNode, dashboardNode, dashboard.ChildPath(path, "alert"))
+		}
+		return nil
+	})
+
+	for _, field := range dashboard.DatasourceFields(model) {
+		dependent := dashboardNode
+		if field.Kind == dashboard.PanelField || field.Kind == dashboard.TargetField {
+			if panelNode, exist := panelNodes[panelPath(field)]; exist {
+				dependent = panelNode
+			}
+		}
+		g.addDatasourceEdge(dependent, field)
+	}
+	return dashboardNode
+}
+
+// AddLibraryPanel adds a library panel and the datasources used by its model
+func (g *Graph) AddLibraryPanel(element *types.LibraryElement) *Node {
+	node := g.declare(LibraryPanelNode, element.UID, element.Name)
+	folderUID := element.FolderUID
+	if len(folderUID) == 0 {
+		folderUID = element.Meta.FolderUID
+	}
+	if len(folderUID) > 0 {
+		g.addEdge(ContainsEdge, node, g.reference(FolderNode, folderUID), "")
+	}
+	// the model of the library panel is wrapped in a dashboard to reuse the walk of the datasources
+	for _, field := range dashboard.DatasourceFields(types.DashboardModel{"panels": []interface{}{element.Model}}) {
+		field.Path = "$" + field.Path[len("$.panels[0]"):]
+		g.addDatasourceEdge(node, field)
+	}
+	return node
+}
+
+// AddAlertRule adds a legacy alert rule defined on a panel
+func (g *Graph) AddAlertRule(alert *types.ResponseGetAlert) *Node {
+	// the alert rules found in the dashboards have the id of their panel, they are replaced by this one
+	panelID := PanelNodeID(alert.DashboardUID, alert.PanelID)
+	g.remove(AlertRuleNode, panelID)
+	node := g.declare(AlertRuleNode, strconv.FormatInt(alert.ID, 10), alert.Name)
+	g.addEdge(AlertsEdge, node, g.reference(PanelNode, panelID), "")
+	g.addEdge(AlertsEdge, node, g.reference(DashboardNode, alert.DashboardUID), "")
+	return node
+}
+
+// AddUnifiedAlertRule adds a Grafana-managed alert rule with the datasources it queries.
+// The panel the rule is linked to, if any, is referenced by a LinksEdge since the rule has its own queries.
+func (g *Graph) AddUnifiedAlertRule(rule *types.AlertRule) *Node {
+	node := g.declare(AlertRuleNode, rule.UID, rule.Title)
+	if len(rule.FolderUID) > 0 {
+		g.addEdge(ContainsEdge, node, g.reference(FolderNode, rule.FolderUID), "")
+	}
+	for i, query := range rule.Data {
+		if len(query.DatasourceUID) == 0 || query.DatasourceUID == types.ExpressionDatasourceUID {
+			continue
+		}
+		g.addDatasourceEdge(node, &dashboard.DatasourceField{
+			Path: dashboard.ChildPath(dashboard.IndexPath("$.data", i), "datasourceUid"),
+			Ref:  dashboard.DatasourceRef{UID: query.DatasourceUID},
+		})
+	}
+	dashboardUID := rule.Annotations[types.AlertRuleDashboardUIDAnnotation]
+	if len(dashboardUID) == 0 {
+		return node
+	}
+	g.addEdge(LinksEdge, node, g.reference(DashboardNode, dashboardUID), "")
+	if panelID, err := strconv.ParseInt(rule.Annotations[types.AlertRulePanelIDAnnotation], 10, 64); err == nil {
+		g.addEdge(LinksEdge, node, g.reference(PanelNode, PanelNodeID(dashboardUID, panelID)), "")
+	}
+	return node
+}
+
+func (g *Graph) addDatasourceEdge(dependent *Node, field *dashboard.DatasourceField) {
+	if dependent == nil || field.Ref.IsVariable() || field.Ref.IsBuiltIn() {
+		return
+	}
+	datasourceNode := g.Datasource(field.Ref)
+	if datasourceNode == nil {
+		datasourceNode = g.reference(DatasourceNode, field.Ref.Key())
+	}
+	g.addEdge(UsesEdge, dependent, datasourceNode, field.Path)
+}
+
+// declare creates the node, or completes it if it has been created before by a reference
+func (g *Graph) declare(kind NodeKind, id string, name string) *Node {
+	node := g.reference(kind, id)
+	node.Name = name
+	node.Missing = false
+	return node
+}
+
+// reference returns the node, it's created as missing if it doesn't exist yet
+func (g *Graph) reference(kind NodeKind, id string) *Node {
+	key := nodeKey(kind, id)
+	if node, exist := g.nodes[key]; exist {
+		return node
+	}
+	node := &Node{Kind: kind, ID: id, Missing: true}
+	g.nodes[key] = node
+	return node
+}
+
+// remove deletes the node and its edges
+func (g *Graph) remove(kind NodeKind, id string) {
+	key := nodeKey(kind, id)
+	if _, exist := g.nodes[key]; !exist {
+		return
+	}
+	delete(g.nodes, key)
+	for _, edge := range g.dependencies[key] {
+		dependencyKey := edge.Dependency.key()
+		g.dependents[dependencyKey] = removeEdge(g.dependents[dependencyKey], edge)
+	}
+	for _, edge := range g.dependents[key] {
+		dependentKey := edge.Dependent.key()
+		g.dependencies[dependentKey] = removeEdge(g.dependencies[dependentKey], edge)
+	}
+	delete(g.dependencies, key)
+	delete(g.dependents, key)
+}
+
+func (g *Graph) addEdge(kind EdgeKind, dependent *Node, dependency *Node, path string) {
+	edge := &Edge{Kind: kind, Dependent: dependent, Dependency: dependency, Path: path}
+	g.dependents[dependency.key()] = append(g.dependents[dependency.key()], edge)
+	g.dependencies[dependent.key()] = append(g.dependencies[dependent.key()], edge)
+}
+
+func removeEdge(edges []*Edge, edge *Edge) []*Edge {
+	result := edges[:0]
+	for _, e := range edges {
+		if e != edge {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// panelPath returns the path of the panel holding the field
+func panelPath(field *dashboard.DatasourceField) string {
+	if field.Kind == dashboard.TargetField {
+		return field.Path[:strings.LastIndex(field.Path, ".targets[")]
+	}
+	return strings.TrimSuffix(field.Path, ".datasource")
+}
+
+func alertName(panel map[string]interface{}) string {
+	alert, _ := panel["alert"].(map[string]interface{})
+	name, _ := alert["name"].(string)
+	return name
+}
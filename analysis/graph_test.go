@@ -0,0 +1,197 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const graphTestDashboard = `{
+  "uid": "abcd",
+  "title": "my dashboard",
+  "panels": [
+    {"id": 1, "type": "timeseries", "title": "cpu", "datasource": {"type": "prometheus", "uid": "prom-uid"},
+     "targets": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "prom-uid"}}],
+     "alert": {"name": "cpu too high"}},
+    {"id": 2, "type": "row", "collapsed": true, "panels": [
+      {"id": 3, "type": "logs", "title": "logs", "datasource": "Loki logs"}
+    ]},
+    {"id": 4, "title": "shared", "libraryPanel": {"uid": "lib-uid", "name": "shared"}}
+  ],
+  "templating": {"list": [
+    {"name": "job", "type": "query", "datasource": {"type": "prometheus", "uid": "prom-uid"}},
+    {"name": "cluster", "type": "query", "datasource": {"type": "prometheus", "uid": "deleted-uid"}}
+  ]}
+}`
+
+func newTestGraph(t *testing.T) *Graph {
+	graph := NewGraph()
+	graph.AddDatasource(&types.DataSource{ID: 1, UID: "prom-uid", Name: "Prometheus main", Type: "prometheus"})
+	graph.AddDatasource(&types.DataSource{ID: 2, UID: "loki-uid", Name: "Loki logs", Type: "loki"})
+	graph.AddFolder("folder-uid", "my folder")
+	model := make(types.DashboardModel)
+	assert.Nil(t, json.Unmarshal([]byte(graphTestDashboard), &model))
+	graph.AddDashboard("folder-uid", model)
+	graph.AddLibraryPanel(&types.LibraryElement{
+		UID:   "lib-uid",
+		Name:  "shared",
+		Model: map[string]interface{}{"type": "stat", "datasource": map[string]interface{}{"type": "loki", "uid": "loki-uid"}},
+	})
+	graph.AddAlertRule(&types.ResponseGetAlert{ID: 7, DashboardUID: "abcd", PanelID: 1, Name: "cpu too high"})
+	return graph
+}
+
+func TestGraph_ReferencedBy(t *testing.T) {
+	graph := newTestGraph(t)
+
+	var references []string
+	for _, edge := range graph.ReferencedBy(graph.Node(DatasourceNode, "prom-uid")) {
+		references = append(references, edge.Dependent.ID+" "+edge.Path)
+	}
+	assert.Equal(t, []string{
+		"abcd/1 $.panels[0].datasource",
+		"abcd/1 $.panels[0].targets[0].datasource",
+		"abcd $.templating.list[0].datasource",
+	}, references)
+
+	references = nil
+	for _, edge := range graph.ReferencedBy(graph.Node(DatasourceNode, "loki-uid")) {
+		references = append(references, edge.Dependent.ID+" "+edge.Path)
+	}
+	assert.Equal(t, []string{"abcd/3 $.panels[1].panels[0].datasource", "lib-uid $.datasource"}, references)
+
+	missing := graph.Node(DatasourceNode, "deleted-uid")
+	assert.True(t, missing.Missing)
+	assert.Len(t, graph.ReferencedBy(missing), 1)
+
+	// the alert rule found in the dashboard has been replaced by the one returned by the API
+	assert.Nil(t, graph.Node(AlertRuleNode, "abcd/1"))
+	assert.Len(t, graph.Nodes(AlertRuleNode), 1)
+}
+
+func TestGraph_AddDashboard_PanelsWithoutID(t *testing.T) {
+	graph := NewGraph()
+	graph.AddDatasource(&types.DataSource{ID: 1, UID: "prom-uid", Name: "Prometheus main", Type: "prometheus"})
+	graph.AddDatasource(&types.DataSource{ID: 2, UID: "loki-uid", Name: "Loki logs", Type: "loki"})
+	model := make(types.DashboardModel)
+	assert.Nil(t, json.Unmarshal([]byte(`{"uid": "abcd", "title": "my dashboard", "panels": [
+		{"type": "timeseries", "title": "cpu", "datasource": {"type": "prometheus", "uid": "prom-uid"}},
+		{"type": "logs", "title": "logs", "datasource": {"type": "loki", "uid": "loki-uid"}}
+	]}`), &model))
+	graph.AddDashboard("", model)
+
+	// the panels aren't merged into a single node, their references are kept on the dashboard
+	assert.Nil(t, graph.Node(PanelNode, PanelNodeID("abcd", 0)))
+	assert.Empty(t, graph.Nodes(PanelNode))
+	for _, uid := range []string{"prom-uid", "loki-uid"} {
+		edges := graph.ReferencedBy(graph.Node(DatasourceNode, uid))
+		assert.Len(t, edges, 1)
+		assert.Equal(t, DashboardNode, edges[0].Dependent.Kind)
+	}
+}
+
+func TestGraph_Impact(t *testing.T) {
+	graph := newTestGraph(t)
+	testSuites := []struct {
+		title    string
+		node     *Node
+		expected map[string]bool
+	}{
+		{
+			title: "datasource",
+			node:  graph.Node(DatasourceNode, "prom-uid"),
+			expected: map[string]bool{
+				"panel:abcd/1":   false,
+				"dashboard:abcd": false,
+				"alert-rule:7":   false,
+			},
+		},
+		{
+			title: "datasource used by a library panel",
+			node:  graph.Node(DatasourceNode, "loki-uid"),
+			expected: map[string]bool{
+				"panel:abcd/3":          false,
+				"library-panel:lib-uid": false,
+				"panel:abcd/4":          false,
+			},
+		},
+		{
+			title: "folder",
+			node:  graph.Node(FolderNode, "folder-uid"),
+			expected: map[string]bool{
+				"dashboard:abcd": true,
+				"panel:abcd/1":   true,
+				"panel:abcd/2":   true,
+				"panel:abcd/3":   true,
+				"panel:abcd/4":   true,
+				"alert-rule:7":   false,
+			},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			result := make(map[string]bool)
+			for _, impact := range graph.Impact(test.node) {
+				result[impact.Node.key()] = impact.Removed
+			}
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestGraph_AddUnifiedAlertRule(t *testing.T) {
+	graph := newTestGraph(t)
+	rule := graph.AddUnifiedAlertRule(&types.AlertRule{
+		UID:       "rule-uid",
+		Title:     "cpu too high",
+		FolderUID: "folder-uid",
+		Data: []*types.AlertQuery{
+			{RefID: "A", DatasourceUID: "prom-uid"},
+			{RefID: "B", DatasourceUID: types.ExpressionDatasourceUID},
+		},
+		Annotations: map[string]string{
+			types.AlertRuleDashboardUIDAnnotation: "abcd",
+			types.AlertRulePanelIDAnnotation:      "1",
+		},
+	})
+
+	var dependencies []string
+	for _, edge := range graph.DependsOn(rule) {
+		dependencies = append(dependencies, string(edge.Kind)+" "+edge.Dependency.key()+" "+edge.Path)
+	}
+	assert.Equal(t, []string{
+		"contains folder:folder-uid ",
+		"uses datasource:prom-uid $.data[0].datasourceUid",
+		"links dashboard:abcd ",
+		"links panel:abcd/1 ",
+	}, dependencies)
+
+	impacts := make(map[string]bool)
+	for _, impact := range graph.Impact(graph.Node(DashboardNode, "abcd")) {
+		impacts[impact.Node.key()] = impact.Removed
+	}
+	// the rule has its own queries, it still works without the dashboard it's linked to
+	assert.NotContains(t, impacts, "alert-rule:rule-uid")
+
+	impacts = make(map[string]bool)
+	for _, impact := range graph.Impact(graph.Node(DatasourceNode, "prom-uid")) {
+		impacts[impact.Node.key()] = impact.Removed
+	}
+	assert.Equal(t, false, impacts["alert-rule:rule-uid"])
+	assert.Contains(t, impacts, "alert-rule:rule-uid")
+}
@@ -0,0 +1,58 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+const alertRuleAPI = "/api/v1/provisioning/alert-rules"
+
+// AlertRuleInterface reads the Grafana-managed alert rules (Grafana >= 9.1).
+// See AlertInterface for the legacy alert rules defined in the panels.
+type AlertRuleInterface interface {
+	// Get returns every alert rule of the current organisation
+	Get() ([]*types.AlertRule, error)
+	GetByUID(uid string) (*types.AlertRule, error)
+}
+
+func newAlertRule(client *grafanahttp.RESTClient) AlertRuleInterface {
+	return &alertRule{
+		client: client,
+	}
+}
+
+type alertRule struct {
+	AlertRuleInterface
+	client *grafanahttp.RESTClient
+}
+
+func (c *alertRule) Get() ([]*types.AlertRule, error) {
+	var result []*types.AlertRule
+	err := c.client.Get(alertRuleAPI).
+		Do().
+		SaveAsObj(&result)
+	return result, err
+}
+
+func (c *alertRule) GetByUID(uid string) (*types.AlertRule, error) {
+	result := &types.AlertRule{}
+	err := c.client.Get(alertRuleAPI).
+		SetSubPath("/:uid").
+		SetPathParam("uid", uid).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
@@ -22,16 +22,22 @@ type ClientInterface interface {
 	Admin() AdminInterface
 	Alerts() AlertInterface
 	AlertNotifications() AlertNotificationInterface
+	AlertRules() AlertRuleInterface
 	Annotations() AnnotationInterface
 	CurrentUser() CurrentUserInterface
 	CurrentOrganisation() CurrentOrgInterface
 	Dashboards() DashboardInterface
 	DataSources() DataSourceInterface
 	Folders() FolderInterface
+	Health() HealthInterface
 	Keys() KeyInterface
+	LibraryElements() LibraryElementInterface
 	Organisations() OrganisationsInterface
 	Playlist() PlaylistInterface
+	PublicDashboards() PublicDashboardInterface
+	Render() RenderInterface
 	Search() SearchInterface
+	ShortURLs() ShortURLInterface
 	Snapshots() SnapshotInterface
 	Teams() TeamInterface
 	Users() UsersInterface
@@ -39,11 +45,14 @@ type ClientInterface interface {
 
 type client struct {
 	restClient *grafanahttp.RESTClient
+	// render is kept so the check of the image renderer is done once for the client
+	render RenderInterface
 }
 
 func NewWithClient(restClient *grafanahttp.RESTClient) ClientInterface {
 	return &client{
 		restClient: restClient,
+		render:     newRender(restClient),
 	}
 }
 
@@ -63,6 +72,10 @@ func (c *client) AlertNotifications() AlertNotificationInterface {
 	return newAlertNotification(c.restClient)
 }
 
+func (c *client) AlertRules() AlertRuleInterface {
+	return newAlertRule(c.restClient)
+}
+
 func (c *client) Annotations() AnnotationInterface {
 	return newAnnotation(c.restClient)
 }
@@ -87,18 +100,38 @@ func (c *client) Folders() FolderInterface {
 	return newFolder(c.restClient)
 }
 
+func (c *client) Health() HealthInterface {
+	return newHealth(c.restClient)
+}
+
 func (c *client) Keys() KeyInterface {
 	return newKey(c.restClient)
 }
 
+func (c *client) LibraryElements() LibraryElementInterface {
+	return newLibraryElement(c.restClient)
+}
+
 func (c *client) Playlist() PlaylistInterface {
 	return newPlaylist(c.restClient)
 }
 
+func (c *client) PublicDashboards() PublicDashboardInterface {
+	return newPublicDashboard(c.restClient)
+}
+
+func (c *client) Render() RenderInterface {
+	return c.render
+}
+
 func (c *client) Search() SearchInterface {
 	return newSearch(c.restClient)
 }
 
+func (c *client) ShortURLs() ShortURLInterface {
+	return newShortURL(c.restClient)
+}
+
 func (c *client) Snapshots() SnapshotInterface {
 	return newSnapshot(c.restClient)
 }
@@ -14,31 +14,82 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/nexucis/grafana-go-client/api/types"
 	"github.com/nexucis/grafana-go-client/grafanahttp"
 )
 
-const dashboardAPI = "/api/dashboards"
+const (
+	dashboardAPI = "/api/dashboards"
+	// modifyMaxAttempts is the number of time Modify tries to save a dashboard before giving up
+	modifyMaxAttempts = 5
+)
+
+// ErrNotModified can be returned by the mutation passed to DashboardInterface.Modify
+// to tell that there is nothing to save.
+var ErrNotModified = errors.New("dashboard not modified")
+
+// DashboardConflictError is returned by DashboardInterface.Modify when the dashboard has been modified concurrently,
+// or its title was already used in the folder, on every attempt to save it.
+type DashboardConflictError struct {
+	UID      string
+	Attempts int
+	// Err is the error returned by Grafana on the last attempt
+	Err error
+}
+
+func (e *DashboardConflictError) Error() string {
+	return fmt.Sprintf("unable to save the dashboard %s after %d attempts because of a conflict: %s", e.UID, e.Attempts, e.Err)
+}
+
+func (e *DashboardConflictError) Unwrap() error {
+	return e.Err
+}
 
 type DashboardInterface interface {
-	GetByUID(string)
+	GetByUID(string) (*types.DashboardWithMeta, error)
 	DeleteByUID(string) error
 	// GetBySlug is deprecated since Grafana 5.0, please use GetByUID instead
-	GetBySlug(string)
+	GetBySlug(string) (*types.DashboardWithMeta, error)
 	// DeleteBySlug is deprecated since Grafana 5.0, please use DeleteByUID instead
 	DeleteBySlug(string) error
 	CalculateDiff()
-	Create()
-	GetHome()
+	// Create creates a new dashboard or updates an existing one
+	Create(*types.SaveDashboard) (*types.SimpleDashboard, error)
+	// Modify fetches the dashboard, applies the mutation and saves it without overwriting a concurrent modification.
+	// When the dashboard has been modified in the meantime or when another dashboard has the same title in the folder,
+	// the whole operation is retried with a fresh copy of the dashboard.
+	// A DashboardConflictError is returned when the dashboard couldn't be saved after several attempts.
+	// If the mutation returns ErrNotModified, nothing is saved and the result is nil.
+	Modify(uid string, message string, mutate func(types.DashboardModel) error) (*types.SimpleDashboard, error)
+	// Move moves the dashboard into the folder, the General folder when folderUID is empty.
+	// Like Modify, a concurrent modification is never overwritten: the move is retried with a fresh copy of the dashboard.
+	Move(uid string, folderUID string, message string) (*types.SimpleDashboard, error)
+	GetHome() (*types.HomeDashboard, error)
+	// GetTags returns every tag used by the dashboards with the number of dashboards using it.
+	// See dashboard.SimilarTags to find the tags that are probably duplicates.
 	GetTags() ([]*types.DashboardTags, error)
-	Import()
+	Import(*types.ImportDashboard) (*types.ImportDashboardResponse, error)
 	GetVersion(int64) ([]*types.DashboardVersion, error)
 	GetVersionByID(int64, int) (*types.DashboardVersion, error)
 	RestoreVersion(int64, int) (*types.SimpleDashboard, error)
 	GetPermissions(int64) ([]*types.FolderOrDashboardPermission, error)
 	UpdatePermissions(int64, []*types.DashboardACLUpdateItem) error
+	// GetVersionsByUID returns a page of versions of the dashboard, the most recent first (Grafana >= 8).
+	// Since Grafana 11, the next page is requested with the continue token of the list, older versions use the start parameter.
+	GetVersionsByUID(uid string, query QueryParameterDashboardVersions) (*types.DashboardVersionList, error)
+	// GetVersionByUID returns a version of the dashboard with its model (Grafana >= 8)
+	GetVersionByUID(uid string, version int) (*types.DashboardVersion, error)
+	// RestoreVersionByUID saves the model of an old version as a new version of the dashboard (Grafana >= 8)
+	RestoreVersionByUID(uid string, version int) (*types.SimpleDashboard, error)
+	// GetPermissionsByUID returns the ACL of the dashboard, including the entries inherited from its folder (Grafana >= 8)
+	GetPermissionsByUID(uid string) ([]*types.FolderOrDashboardPermission, error)
+	// UpdatePermissionsByUID replaces the ACL of the dashboard (Grafana >= 8)
+	UpdatePermissionsByUID(uid string, items []*types.DashboardACLUpdateItem) error
 }
 
 func newDashboard(client *grafanahttp.RESTClient) DashboardInterface {
@@ -52,8 +103,14 @@ type dashboard struct {
 	client *grafanahttp.RESTClient
 }
 
-func (c *dashboard) GetByUID(string) {
-	// not yet implemented, need to specify the dashboard struct
+func (c *dashboard) GetByUID(uid string) (*types.DashboardWithMeta, error) {
+	result := &types.DashboardWithMeta{}
+	err := c.client.Get(dashboardAPI).
+		SetSubPath("/uid/:uid").
+		SetPathParam("uid", uid).
+		Do().
+		SaveAsObj(result)
+	return result, err
 }
 
 func (c *dashboard) DeleteByUID(uid string) error {
@@ -65,8 +122,14 @@ func (c *dashboard) DeleteByUID(uid string) error {
 }
 
 // GetBySlug is deprecated since Grafana 5.0, please use GetByUID instead
-func (c *dashboard) GetBySlug(string) {
-	// not yet implemented, need to specify the dashboard struct
+func (c *dashboard) GetBySlug(slug string) (*types.DashboardWithMeta, error) {
+	result := &types.DashboardWithMeta{}
+	err := c.client.Get(dashboardAPI).
+		SetSubPath("/db/:slug").
+		SetPathParam("slug", slug).
+		Do().
+		SaveAsObj(result)
+	return result, err
 }
 
 // DeleteBySlug is deprecated since Grafana 5.0, please use DeleteByUID instead
@@ -82,12 +145,73 @@ func (c *dashboard) CalculateDiff() {
 
 }
 
-func (c *dashboard) Create() {
-	// not yet implemented, need to specify the dashboard struct
+func (c *dashboard) Create(dashboard *types.SaveDashboard) (*types.SimpleDashboard, error) {
+	result := &types.SimpleDashboard{}
+	err := c.client.Post(dashboardAPI).
+		SetSubPath("/db").
+		Body(dashboard).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
+
+func (c *dashboard) Modify(uid string, message string, mutate func(types.DashboardModel) error) (*types.SimpleDashboard, error) {
+	return c.modify(uid, message, nil, mutate)
+}
+
+func (c *dashboard) Move(uid string, folderUID string, message string) (*types.SimpleDashboard, error) {
+	return c.modify(uid, message, &folderUID, func(types.DashboardModel) error {
+		return nil
+	})
+}
+
+// modify implements Modify. When folderUID is not nil, the dashboard is saved in this folder instead of its current one.
+func (c *dashboard) modify(uid string, message string, folderUID *string, mutate func(types.DashboardModel) error) (*types.SimpleDashboard, error) {
+	var lastErr error
+	for attempt := 1; attempt <= modifyMaxAttempts; attempt++ {
+		current, err := c.GetByUID(uid)
+		if err != nil {
+			return nil, err
+		}
+		version := current.Dashboard["version"]
+		if err := mutate(current.Dashboard); err != nil {
+			if errors.Is(err, ErrNotModified) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		// the version is the one fetched, whatever the mutation did, so Grafana can detect a concurrent modification
+		current.Dashboard["version"] = version
+		save := &types.SaveDashboard{
+			Dashboard: current.Dashboard,
+			FolderID:  current.Meta.FolderID,
+			FolderUID: current.Meta.FolderUID,
+			Message:   message,
+			Overwrite: false,
+		}
+		if folderUID != nil {
+			save.FolderID = 0
+			save.FolderUID = *folderUID
+		}
+		result, err := c.Create(save)
+		if err == nil {
+			return result, nil
+		}
+		if !isDashboardConflict(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, &DashboardConflictError{UID: uid, Attempts: modifyMaxAttempts, Err: lastErr}
 }
 
-func (c *dashboard) GetHome() {
-	// not yet implemented, need to specify the dashboard struct
+func (c *dashboard) GetHome() (*types.HomeDashboard, error) {
+	result := &types.HomeDashboard{}
+	err := c.client.Get(dashboardAPI).
+		SetSubPath("/home").
+		Do().
+		SaveAsObj(result)
+	return result, err
 }
 
 func (c *dashboard) GetTags() ([]*types.DashboardTags, error) {
@@ -95,12 +219,18 @@ func (c *dashboard) GetTags() ([]*types.DashboardTags, error) {
 	err := c.client.Get(dashboardAPI).
 		SetSubPath("/tags").
 		Do().
-		SaveAsObj(result)
+		SaveAsObj(&result)
 	return result, err
 }
 
-func (c *dashboard) Import() {
-
+func (c *dashboard) Import(importDashboard *types.ImportDashboard) (*types.ImportDashboardResponse, error) {
+	result := &types.ImportDashboardResponse{}
+	err := c.client.Post(dashboardAPI).
+		SetSubPath("/import").
+		Body(importDashboard).
+		Do().
+		SaveAsObj(result)
+	return result, err
 }
 
 func (c *dashboard) GetVersion(dashboardID int64) ([]*types.DashboardVersion, error) {
@@ -162,3 +292,75 @@ func (c *dashboard) UpdatePermissions(dashboardID int64, items []*types.Dashboar
 		Do().
 		Error()
 }
+
+func (c *dashboard) GetVersionsByUID(uid string, query QueryParameterDashboardVersions) (*types.DashboardVersionList, error) {
+	result := &types.DashboardVersionList{}
+	err := c.client.Get(dashboardAPI).
+		SetSubPath("/uid/:uid/versions").
+		SetPathParam("uid", uid).
+		Query(&query).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
+
+func (c *dashboard) GetVersionByUID(uid string, version int) (*types.DashboardVersion, error) {
+	result := &types.DashboardVersion{}
+	err := c.client.Get(dashboardAPI).
+		SetSubPath("/uid/:uid/versions/:version").
+		SetPathParam("uid", uid).
+		SetPathParam("version", strconv.Itoa(version)).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
+
+func (c *dashboard) RestoreVersionByUID(uid string, version int) (*types.SimpleDashboard, error) {
+	body := struct {
+		Version int `json:"version" binding:"Required"`
+	}{
+		Version: version,
+	}
+	result := &types.SimpleDashboard{}
+	err := c.client.Post(dashboardAPI).
+		SetSubPath("/uid/:uid/restore").
+		SetPathParam("uid", uid).
+		Body(body).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
+
+func (c *dashboard) GetPermissionsByUID(uid string) ([]*types.FolderOrDashboardPermission, error) {
+	var result []*types.FolderOrDashboardPermission
+	err := c.client.Get(dashboardAPI).
+		SetSubPath("/uid/:uid/permissions").
+		SetPathParam("uid", uid).
+		Do().
+		SaveAsObj(&result)
+	return result, err
+}
+
+func (c *dashboard) UpdatePermissionsByUID(uid string, items []*types.DashboardACLUpdateItem) error {
+	body := struct {
+		Items []*types.DashboardACLUpdateItem `json:"items"`
+	}{Items: items}
+
+	return c.client.Post(dashboardAPI).
+		SetSubPath("/uid/:uid/permissions").
+		SetPathParam("uid", uid).
+		Body(body).
+		Do().
+		Error()
+}
+
+// isDashboardConflict returns true if the error has been sent by Grafana because the dashboard saved
+// has been modified in the meantime or because another dashboard has the same title in the folder.
+// In both cases, the dashboard is fetched again so the mutation sees the latest state before retrying.
+func isDashboardConflict(err error) bool {
+	requestErr, ok := err.(*grafanahttp.RequestError)
+	if !ok || requestErr.StatusCode != http.StatusPreconditionFailed {
+		return false
+	}
+	return requestErr.Status == "version-mismatch" || requestErr.Status == "name-exists"
+}
@@ -0,0 +1,341 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboard_Create(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	dashboard := initDashboardTest(t)
+	result, err := dashboard.Create(&types.SaveDashboard{
+		Dashboard: types.DashboardModel{"uid": "my-dashboard", "title": "my dashboard"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "my-dashboard", result.UID)
+	assert.Equal(t, 1, result.Version)
+
+	savedDashboard, err := dashboard.GetByUID("my-dashboard")
+	assert.Nil(t, err)
+	assert.Equal(t, "my dashboard", savedDashboard.Dashboard.Title())
+
+	// clean test
+	removeDashboard(t, "my-dashboard")
+}
+
+func TestDashboard_Modify(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	dashboard := initDashboardTest(t)
+	_, err := dashboard.Create(&types.SaveDashboard{
+		Dashboard: types.DashboardModel{"uid": "my-dashboard", "title": "my dashboard"},
+	})
+	assert.Nil(t, err)
+
+	attempts := 0
+	result, err := dashboard.Modify("my-dashboard", "change the title", func(model types.DashboardModel) error {
+		attempts++
+		if attempts == 1 {
+			// simulate a concurrent modification
+			_, concurrentErr := dashboard.Create(&types.SaveDashboard{
+				Dashboard: types.DashboardModel{"uid": "my-dashboard", "title": "concurrent title", "version": model["version"]},
+			})
+			assert.Nil(t, concurrentErr)
+		}
+		model["title"] = "my new title"
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 3, result.Version)
+
+	savedDashboard, err := dashboard.GetByUID("my-dashboard")
+	assert.Nil(t, err)
+	assert.Equal(t, "my new title", savedDashboard.Dashboard.Title())
+
+	// clean test
+	removeDashboard(t, "my-dashboard")
+}
+
+func initDashboardTest(t *testing.T) DashboardInterface {
+	httpClient, err := getRestClientWithBasicAuth()
+	assert.Nil(t, err)
+	return newDashboard(httpClient)
+}
+
+func removeDashboard(t *testing.T, uids ...string) {
+	dashboardClient := initDashboardTest(t)
+	for _, uid := range uids {
+		dashboardClient.DeleteByUID(uid) // nolint: errcheck
+	}
+}
+
+func TestDashboard_Modify_Conflicts(t *testing.T) {
+	testSuites := []struct {
+		title         string
+		status        string
+		expectedSaves int
+	}{
+		{
+			title:         "version mismatch is retried",
+			status:        "version-mismatch",
+			expectedSaves: modifyMaxAttempts,
+		},
+		{
+			title:         "name exists is retried",
+			status:        "name-exists",
+			expectedSaves: modifyMaxAttempts,
+		},
+		{
+			title:         "other precondition failure is not retried",
+			status:        "plugin-dashboard",
+			expectedSaves: 1,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			saves := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					_, _ = w.Write([]byte(`{"meta": {}, "dashboard": {"uid": "abcd", "title": "my dashboard", "version": 1}}`))
+					return
+				}
+				saves++
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`{"message": "conflict", "status": "` + test.status + `"}`))
+			}))
+			defer server.Close()
+
+			rest, err := grafanahttp.NewWithURL(server.URL)
+			assert.Nil(t, err)
+			_, err = newDashboard(rest).Modify("abcd", "", func(model types.DashboardModel) error {
+				model["title"] = "new title"
+				return nil
+			})
+			assert.NotNil(t, err)
+			_, isConflict := err.(*DashboardConflictError)
+			assert.Equal(t, test.expectedSaves > 1, isConflict)
+			assert.Equal(t, test.expectedSaves, saves)
+		})
+	}
+}
+
+func TestDashboard_Move(t *testing.T) {
+	// the dashboard is modified concurrently between the first fetch and the first save
+	version := 1
+	var saved []*types.SaveDashboard
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"meta": {"folderId": 3, "folderUid": "old"}, "dashboard": {"uid": "abcd", "title": "my dashboard v%d", "version": %d}}`, version, version)))
+			return
+		}
+		body := &types.SaveDashboard{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(body))
+		saved = append(saved, body)
+		if version == 1 {
+			version = 2
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`{"message": "version mismatch", "status": "version-mismatch"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"uid": "abcd", "version": 3, "status": "success"}`))
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	result, err := newDashboard(rest).Move("abcd", "new", "moved")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Version)
+	if assert.Len(t, saved, 2) {
+		// the concurrent modification is kept, not overwritten
+		last := saved[1]
+		assert.Equal(t, "my dashboard v2", last.Dashboard.Title())
+		assert.Equal(t, float64(2), last.Dashboard["version"])
+		assert.Equal(t, "new", last.FolderUID)
+		assert.Zero(t, last.FolderID)
+		assert.Equal(t, "moved", last.Message)
+		assert.False(t, last.Overwrite)
+	}
+}
+
+func TestDashboard_VersionsByUID(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	dashboard := initDashboardTest(t)
+	for _, title := range []string{"first title", "second title"} {
+		_, err := dashboard.Create(&types.SaveDashboard{
+			Dashboard: types.DashboardModel{"uid": "my-dashboard", "title": title},
+			Overwrite: true,
+		})
+		assert.Nil(t, err)
+	}
+
+	versions, err := dashboard.GetVersionsByUID("my-dashboard", QueryParameterDashboardVersions{Limit: 1})
+	assert.Nil(t, err)
+	assert.Len(t, versions.Versions, 1)
+	assert.Equal(t, 2, versions.Versions[0].Version)
+
+	version, err := dashboard.GetVersionByUID("my-dashboard", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, version.Version)
+
+	restored, err := dashboard.RestoreVersionByUID("my-dashboard", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, restored.Version)
+
+	savedDashboard, err := dashboard.GetByUID("my-dashboard")
+	assert.Nil(t, err)
+	assert.Equal(t, "first title", savedDashboard.Dashboard.Title())
+
+	// clean test
+	removeDashboard(t, "my-dashboard")
+}
+
+func TestDashboard_PermissionsByUID(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	dashboard := initDashboardTest(t)
+	_, err := dashboard.Create(&types.SaveDashboard{
+		Dashboard: types.DashboardModel{"uid": "my-dashboard", "title": "my dashboard"},
+	})
+	assert.Nil(t, err)
+
+	viewer := types.RoleViewer
+	err = dashboard.UpdatePermissionsByUID("my-dashboard", []*types.DashboardACLUpdateItem{
+		{Role: &viewer, Permission: types.PermissionEdit},
+	})
+	assert.Nil(t, err)
+
+	permissions, err := dashboard.GetPermissionsByUID("my-dashboard")
+	assert.Nil(t, err)
+	var explicit []*types.FolderOrDashboardPermission
+	for _, permission := range permissions {
+		if !permission.Inherited {
+			explicit = append(explicit, permission)
+		}
+	}
+	assert.Len(t, explicit, 1)
+	assert.Equal(t, types.PermissionEdit, explicit[0].Permission)
+
+	// clean test
+	removeDashboard(t, "my-dashboard")
+}
+
+func TestDashboardVersionList_UnmarshalJSON(t *testing.T) {
+	testSuites := []struct {
+		data          string
+		continueToken string
+	}{
+		{data: `[{"version": 2}, {"version": 1}]`},
+		{data: `{"continueToken": "", "versions": [{"version": 2}, {"version": 1}]}`},
+		{data: `{"continueToken": "next-page", "versions": [{"version": 2}, {"version": 1}]}`, continueToken: "next-page"},
+	}
+	for _, test := range testSuites {
+		list := &types.DashboardVersionList{}
+		assert.Nil(t, json.Unmarshal([]byte(test.data), list))
+		assert.Len(t, list.Versions, 2, test.data)
+		assert.Equal(t, 2, list.Versions[0].Version, test.data)
+		assert.Equal(t, test.continueToken, list.ContinueToken, test.data)
+	}
+}
+
+func TestDashboard_GetTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/dashboards/tags", r.URL.Path)
+		_, _ = w.Write([]byte(`[{"term": "prod", "count": 3}, {"term": "production", "count": 12}]`))
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	tags, err := newDashboard(rest).GetTags()
+	assert.Nil(t, err)
+	assert.Equal(t, []*types.DashboardTags{{Term: "prod", Count: 3}, {Term: "production", Count: 12}}, tags)
+}
+
+func TestDashboard_GetByUID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/dashboards/uid/abcd", r.URL.Path)
+		_, _ = w.Write([]byte(`{"meta": {"folderUid": "team-a", "version": 3}, "dashboard": {"uid": "abcd", "title": "my dashboard", "version": 3}}`))
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	result, err := newDashboard(rest).GetByUID("abcd")
+	assert.Nil(t, err)
+	assert.Equal(t, "team-a", result.Meta.FolderUID)
+	assert.Equal(t, "my dashboard", result.Dashboard.Title())
+	assert.Equal(t, 3, result.Dashboard.Version())
+}
+
+func TestDashboard_Import(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/dashboards/import", r.URL.Path)
+		body := make(map[string]interface{})
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"dashboard": map[string]interface{}{"title": "imported"},
+			"overwrite": true,
+			"inputs": []interface{}{
+				map[string]interface{}{"name": "DS_PROMETHEUS", "type": "datasource", "pluginId": "prometheus", "value": "prom-uid"},
+			},
+			"folderUid": "team-a",
+		}, body)
+		_, _ = w.Write([]byte(`{"uid": "new-uid", "title": "imported", "imported": true, "folderUid": "team-a", "dashboardId": 12}`))
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	result, err := newDashboard(rest).Import(&types.ImportDashboard{
+		Dashboard: types.DashboardModel{"title": "imported"},
+		Overwrite: true,
+		Inputs:    []*types.ImportDashboardInput{{Name: "DS_PROMETHEUS", Type: "datasource", PluginID: "prometheus", Value: "prom-uid"}},
+		FolderUID: "team-a",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.ImportDashboardResponse{UID: "new-uid", Title: "imported", Imported: true, FolderUID: "team-a", DashboardID: 12}, result)
+}
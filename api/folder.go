@@ -15,18 +15,47 @@ package api
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/nexucis/grafana-go-client/api/types"
 	"github.com/nexucis/grafana-go-client/grafanahttp"
 )
 
-const folderAPI = "/api/folders"
+const (
+	folderAPI = "/api/folders"
+	// foldersDefaultLimit is the page size used by GetChildren and GetTree
+	foldersDefaultLimit = 1000
+	// nestedFoldersFeature is the feature toggle enabling the nested folders, from Grafana 10 to Grafana 11
+	nestedFoldersFeature = "nestedFolders"
+	// nestedFoldersMajorVersion is the first version of Grafana where the nested folders can't be disabled
+	nestedFoldersMajorVersion = 12
+)
 
 type FolderInterface interface {
 	Get(int) ([]*types.SimpleFolder, error)
+	// List returns a page of the folders at the root, or of the subfolders of query.ParentUID
+	List(QueryParameterFolders) ([]*types.SimpleFolder, error)
+	// SupportsNestedFolders returns true when Grafana can store folders inside other folders.
+	// It's the case since Grafana 10 when the feature nestedFolders is enabled, and always since Grafana 12.
+	SupportsNestedFolders() (bool, error)
+	// GetChildren walks through every page and returns the direct subfolders of the folder.
+	// The folders at the root are returned when parentUID is empty.
+	// When Grafana doesn't support nested folders, a folder has no subfolder.
+	GetChildren(parentUID string) ([]*types.SimpleFolder, error)
+	// GetParents returns the ancestors of the folder, from the root to its direct parent.
+	GetParents(uid string) ([]*types.Folder, error)
+	// GetDescendantCounts returns the number of resources stored in the folder and in its subfolders
+	GetDescendantCounts(uid string) (*types.FolderDescendantCounts, error)
+	// GetTree builds the hierarchy of every folder of the organisation.
+	// It requests the subfolders of each folder, so it can take a while with a lot of folders.
+	GetTree() (*types.FolderTree, error)
 	GetByID(id int64) (*types.Folder, error)
 	GetByUID(string) (*types.Folder, error)
 	Create(string, string) (*types.Folder, error)
+	// CreateFolder creates a folder, possibly inside another one
+	CreateFolder(*types.CreateFolder) (*types.Folder, error)
+	// Move moves the folder into the folder parentUID, or at the root when parentUID is empty
+	Move(uid string, parentUID string) (*types.Folder, error)
 	Update(string, *types.UpdateFolder) (*types.Folder, error)
 	Delete(string) error
 	GetPermissions(string) ([]*types.FolderOrDashboardPermission, error)
@@ -57,6 +86,120 @@ func (c *folder) Get(limit int) ([]*types.SimpleFolder, error) {
 	return result, err
 }
 
+func (c *folder) List(query QueryParameterFolders) ([]*types.SimpleFolder, error) {
+	var result []*types.SimpleFolder
+	err := c.client.Get(folderAPI).
+		Query(&query).
+		Do().
+		SaveAsObj(&result)
+	return result, err
+}
+
+func (c *folder) SupportsNestedFolders() (bool, error) {
+	result := &struct {
+		FeatureToggles map[string]bool `json:"featureToggles"`
+		BuildInfo      struct {
+			Version string `json:"version"`
+		} `json:"buildInfo"`
+	}{}
+	err := c.client.Get(frontendSettingsAPI).
+		Do().
+		SaveAsObj(result)
+	if err != nil {
+		return false, err
+	}
+	if result.FeatureToggles[nestedFoldersFeature] {
+		return true, nil
+	}
+	major, err := strconv.Atoi(strings.SplitN(result.BuildInfo.Version, ".", 2)[0])
+	return err == nil && major >= nestedFoldersMajorVersion, nil
+}
+
+func (c *folder) GetChildren(parentUID string) ([]*types.SimpleFolder, error) {
+	if len(parentUID) > 0 {
+		// without nested folders, Grafana ignores the parent and returns the folders at the root
+		nested, err := c.SupportsNestedFolders()
+		if err != nil {
+			return nil, err
+		}
+		if !nested {
+			return []*types.SimpleFolder{}, nil
+		}
+	}
+	return c.listChildren(parentUID)
+}
+
+// listChildren returns every page of the subfolders of the folder, without checking that Grafana supports nested folders
+func (c *folder) listChildren(parentUID string) ([]*types.SimpleFolder, error) {
+	query := QueryParameterFolders{ParentUID: parentUID, Limit: foldersDefaultLimit}
+	var result []*types.SimpleFolder
+	for page := 1; ; page++ {
+		query.Page = page
+		folders, err := c.List(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range folders {
+			// some versions of Grafana don't return the parent
+			if len(f.ParentUID) == 0 {
+				f.ParentUID = parentUID
+			}
+		}
+		result = append(result, folders...)
+		if len(folders) < query.Limit {
+			return result, nil
+		}
+	}
+}
+
+func (c *folder) GetParents(uid string) ([]*types.Folder, error) {
+	result, err := c.GetByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	return result.Parents, nil
+}
+
+func (c *folder) GetDescendantCounts(uid string) (*types.FolderDescendantCounts, error) {
+	result := &types.FolderDescendantCounts{}
+	err := c.client.Get(folderAPI).
+		SetSubPath("/:uid/counts").
+		SetPathParam("uid", uid).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
+
+func (c *folder) GetTree() (*types.FolderTree, error) {
+	nested, err := c.SupportsNestedFolders()
+	if err != nil {
+		return nil, err
+	}
+	var folders []*types.SimpleFolder
+	// a folder already seen must not be visited again, in case the response of Grafana is inconsistent
+	seen := make(map[string]bool)
+	parents := []string{""}
+	for len(parents) > 0 {
+		parentUID := parents[0]
+		parents = parents[1:]
+		children, err := c.listChildren(parentUID)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if seen[child.UID] {
+				continue
+			}
+			seen[child.UID] = true
+			folders = append(folders, child)
+			if nested {
+				parents = append(parents, child.UID)
+			}
+		}
+	}
+	return types.NewFolderTree(folders), nil
+}
+
 func (c *folder) GetByID(id int64) (*types.Folder, error) {
 	result := &types.Folder{}
 	err := c.client.Get(folderAPI).
@@ -78,14 +221,24 @@ func (c *folder) GetByUID(uid string) (*types.Folder, error) {
 }
 
 func (c *folder) Create(title string, uid string) (*types.Folder, error) {
-	body := struct {
-		Title string `json:"title"`
-		UID   string `json:"uid"`
-	}{Title: title, UID: uid}
+	return c.CreateFolder(&types.CreateFolder{Title: title, UID: uid})
+}
 
+func (c *folder) CreateFolder(folder *types.CreateFolder) (*types.Folder, error) {
 	result := &types.Folder{}
 	err := c.client.Post(folderAPI).
-		Body(body).
+		Body(folder).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
+
+func (c *folder) Move(uid string, parentUID string) (*types.Folder, error) {
+	result := &types.Folder{}
+	err := c.client.Post(folderAPI).
+		SetSubPath("/:uid/move").
+		SetPathParam("uid", uid).
+		Body(&types.MoveFolder{ParentUID: parentUID}).
 		Do().
 		SaveAsObj(result)
 	return result, err
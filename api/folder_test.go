@@ -0,0 +1,194 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func initFolderTest(t *testing.T) FolderInterface {
+	httpClient, err := getRestClientWithBasicAuth()
+	assert.Nil(t, err)
+	return newFolder(httpClient)
+}
+
+func removeFolder(t *testing.T, uids ...string) {
+	folderClient := initFolderTest(t)
+	for _, uid := range uids {
+		folderClient.Delete(uid) // nolint: errcheck
+	}
+}
+
+func TestFolder_GetTree(t *testing.T) {
+	children := map[string][]*types.SimpleFolder{
+		"":     {{UID: "a", Title: "A"}, {UID: "b", Title: "B"}},
+		"a":    {{UID: "a1", Title: "A1"}, {UID: "a2", Title: "A2"}},
+		"a1":   {{UID: "a1-x", Title: "A1 X"}},
+		"b":    {},
+		"a2":   {},
+		"a1-x": {},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == frontendSettingsAPI {
+			_, _ = w.Write([]byte(`{"buildInfo": {"version": "10.4.2"}, "featureToggles": {"nestedFolders": true}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(children[r.URL.Query().Get("parentUid")])
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	tree, err := newFolder(rest).GetTree()
+	assert.Nil(t, err)
+
+	var walked []string
+	tree.Walk(func(node *types.FolderNode, depth int) {
+		walked = append(walked, node.Folder.UID)
+	})
+	assert.Equal(t, []string{"a", "a1", "a1-x", "a2", "b"}, walked)
+
+	var breadcrumbs []string
+	for _, folder := range tree.Find("a1-x").Path() {
+		breadcrumbs = append(breadcrumbs, folder.Title)
+	}
+	assert.Equal(t, []string{"A", "A1", "A1 X"}, breadcrumbs)
+	assert.Len(t, tree.Descendants("a"), 3)
+	assert.Nil(t, tree.Find("unknown"))
+}
+
+// newPagedFolderServer returns a fake Grafana having 1500 folders, so they are returned in two pages.
+// With nested folders, they are all stored in the folder "parent", otherwise they are all at the root.
+func newPagedFolderServer(t *testing.T, settings string, nested bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == frontendSettingsAPI {
+			_, _ = w.Write([]byte(settings))
+			return
+		}
+		assert.Equal(t, folderAPI, r.URL.Path)
+		parentUID := r.URL.Query().Get("parentUid")
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		folders := []*types.SimpleFolder{}
+		if !nested || parentUID == "parent" {
+			for i := (page - 1) * limit; i < page*limit && i < 1500; i++ {
+				folders = append(folders, &types.SimpleFolder{UID: "f" + strconv.Itoa(i)})
+			}
+		} else if len(parentUID) == 0 && page == 1 {
+			folders = append(folders, &types.SimpleFolder{UID: "parent"})
+		}
+		_ = json.NewEncoder(w).Encode(folders)
+	}))
+}
+
+func TestFolder_GetChildren(t *testing.T) {
+	testSuites := []struct {
+		title            string
+		settings         string
+		nested           bool
+		expectedChildren int
+		expectedTree     int
+	}{
+		{
+			title:            "nested folders enabled by the feature toggle",
+			settings:         `{"buildInfo": {"version": "10.4.2"}, "featureToggles": {"nestedFolders": true}}`,
+			nested:           true,
+			expectedChildren: 1500,
+			expectedTree:     1501,
+		},
+		{
+			title:            "nested folders always enabled",
+			settings:         `{"buildInfo": {"version": "12.0.1"}, "featureToggles": {}}`,
+			nested:           true,
+			expectedChildren: 1500,
+			expectedTree:     1501,
+		},
+		{
+			title:            "no nested folders",
+			settings:         `{"buildInfo": {"version": "9.5.3"}, "featureToggles": {}}`,
+			nested:           false,
+			expectedChildren: 0,
+			expectedTree:     1500,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			server := newPagedFolderServer(t, test.settings, test.nested)
+			defer server.Close()
+
+			rest, err := grafanahttp.NewWithURL(server.URL)
+			assert.Nil(t, err)
+			folderClient := newFolder(rest)
+
+			children, err := folderClient.GetChildren("parent")
+			assert.Nil(t, err)
+			assert.Len(t, children, test.expectedChildren)
+			for _, child := range children {
+				assert.Equal(t, "parent", child.ParentUID)
+			}
+
+			tree, err := folderClient.GetTree()
+			assert.Nil(t, err)
+			count := 0
+			tree.Walk(func(node *types.FolderNode, depth int) {
+				count++
+			})
+			assert.Equal(t, test.expectedTree, count)
+		})
+	}
+}
+
+func TestFolder_Nested(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	folderClient := initFolderTest(t)
+	_, err := folderClient.CreateFolder(&types.CreateFolder{UID: "parent", Title: "parent"})
+	assert.Nil(t, err)
+	_, err = folderClient.CreateFolder(&types.CreateFolder{UID: "child", Title: "child", ParentUID: "parent"})
+	assert.Nil(t, err)
+	_, err = folderClient.CreateFolder(&types.CreateFolder{UID: "other", Title: "other"})
+	assert.Nil(t, err)
+
+	children, err := folderClient.GetChildren("parent")
+	assert.Nil(t, err)
+	assert.Len(t, children, 1)
+
+	moved, err := folderClient.Move("child", "other")
+	assert.Nil(t, err)
+	assert.Equal(t, "other", moved.ParentUID)
+
+	parents, err := folderClient.GetParents("child")
+	assert.Nil(t, err)
+	assert.Len(t, parents, 1)
+	assert.Equal(t, "other", parents[0].UID)
+
+	counts, err := folderClient.GetDescendantCounts("other")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), counts.Folders)
+
+	// clean test
+	removeFolder(t, "parent", "other")
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+const healthAPI = "/api/health"
+
+type HealthInterface interface {
+	// Get returns the version of Grafana and the state of its database. It doesn't require to be authenticated.
+	Get() (*types.Health, error)
+}
+
+func newHealth(client *grafanahttp.RESTClient) HealthInterface {
+	return &health{
+		client: client,
+	}
+}
+
+type health struct {
+	HealthInterface
+	client *grafanahttp.RESTClient
+}
+
+func (c *health) Get() (*types.Health, error) {
+	result := &types.Health{}
+	err := c.client.Get(healthAPI).
+		Do().
+		SaveAsObj(result)
+	return result, err
+}
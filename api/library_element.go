@@ -0,0 +1,163 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+const libraryElementAPI = "/api/library-elements"
+
+// libraryElementsDefaultPerPage is the page size used by SearchAll when the query doesn't set it
+const libraryElementsDefaultPerPage = 100
+
+type LibraryElementInterface interface {
+	// Search returns one page of the library elements matching the query
+	Search(QueryParameterLibraryElements) (*types.LibraryElementSearchResult, error)
+	// SearchAll walks through every page and returns all library elements matching the query.
+	// The field Page of the query is ignored.
+	SearchAll(QueryParameterLibraryElements) ([]*types.LibraryElement, error)
+	GetByUID(string) (*types.LibraryElement, error)
+	// GetByName returns every library element having this name. The name is unique only in a folder.
+	GetByName(string) ([]*types.LibraryElement, error)
+	Create(*types.CreateLibraryElement) (*types.LibraryElement, error)
+	Patch(string, *types.PatchLibraryElement) (*types.LibraryElement, error)
+	// Delete fails if the library element is still used by a dashboard
+	Delete(string) error
+	// GetConnections returns the dashboards using the library element
+	GetConnections(string) ([]*types.LibraryElementConnection, error)
+}
+
+func newLibraryElement(client *grafanahttp.RESTClient) LibraryElementInterface {
+	return &libraryElement{
+		client: client,
+	}
+}
+
+type libraryElement struct {
+	LibraryElementInterface
+	client *grafanahttp.RESTClient
+}
+
+// Grafana wraps every response of the library elements API in a field "result"
+type libraryElementResponse struct {
+	Result *types.LibraryElement `json:"result"`
+}
+
+type libraryElementListResponse struct {
+	Result []*types.LibraryElement `json:"result"`
+}
+
+type libraryElementSearchResponse struct {
+	Result *types.LibraryElementSearchResult `json:"result"`
+}
+
+type libraryElementConnectionsResponse struct {
+	Result []*types.LibraryElementConnection `json:"result"`
+}
+
+func (c *libraryElement) Search(query QueryParameterLibraryElements) (*types.LibraryElementSearchResult, error) {
+	response := &libraryElementSearchResponse{}
+	err := c.client.Get(libraryElementAPI).
+		Query(&query).
+		Do().
+		SaveAsObj(response)
+
+	return response.Result, err
+}
+
+func (c *libraryElement) SearchAll(query QueryParameterLibraryElements) ([]*types.LibraryElement, error) {
+	if query.PerPage <= 0 {
+		query.PerPage = libraryElementsDefaultPerPage
+	}
+	var result []*types.LibraryElement
+	for page := 1; ; page++ {
+		query.Page = page
+		searchResult, err := c.Search(query)
+		if err != nil {
+			return nil, err
+		}
+		if searchResult == nil {
+			return result, nil
+		}
+		result = append(result, searchResult.Elements...)
+		if len(searchResult.Elements) < query.PerPage || int64(len(result)) >= searchResult.TotalCount {
+			return result, nil
+		}
+	}
+}
+
+func (c *libraryElement) GetByUID(uid string) (*types.LibraryElement, error) {
+	response := &libraryElementResponse{}
+	err := c.client.Get(libraryElementAPI).
+		SetSubPath("/:uid").
+		SetPathParam("uid", uid).
+		Do().
+		SaveAsObj(response)
+
+	return response.Result, err
+}
+
+func (c *libraryElement) GetByName(name string) ([]*types.LibraryElement, error) {
+	response := &libraryElementListResponse{}
+	err := c.client.Get(libraryElementAPI).
+		SetSubPath("/name/:name").
+		SetPathParam("name", name).
+		Do().
+		SaveAsObj(response)
+
+	return response.Result, err
+}
+
+func (c *libraryElement) Create(element *types.CreateLibraryElement) (*types.LibraryElement, error) {
+	response := &libraryElementResponse{}
+	err := c.client.Post(libraryElementAPI).
+		Body(element).
+		Do().
+		SaveAsObj(response)
+
+	return response.Result, err
+}
+
+func (c *libraryElement) Patch(uid string, element *types.PatchLibraryElement) (*types.LibraryElement, error) {
+	response := &libraryElementResponse{}
+	err := c.client.Patch(libraryElementAPI).
+		SetSubPath("/:uid").
+		SetPathParam("uid", uid).
+		Body(element).
+		Do().
+		SaveAsObj(response)
+
+	return response.Result, err
+}
+
+func (c *libraryElement) Delete(uid string) error {
+	return c.client.Delete(libraryElementAPI).
+		SetSubPath("/:uid").
+		SetPathParam("uid", uid).
+		Do().
+		Error()
+}
+
+func (c *libraryElement) GetConnections(uid string) ([]*types.LibraryElementConnection, error) {
+	response := &libraryElementConnectionsResponse{}
+	err := c.client.Get(libraryElementAPI).
+		SetSubPath("/:uid/connections").
+		SetPathParam("uid", uid).
+		Do().
+		SaveAsObj(response)
+
+	return response.Result, err
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibraryElement_CreateAndPatch(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	libraryElement := initLibraryElementTest(t)
+	element, err := libraryElement.Create(&types.CreateLibraryElement{
+		UID:   "my-library-panel",
+		Name:  "my library panel",
+		Kind:  types.LibraryPanelKind,
+		Model: map[string]interface{}{"type": "text", "title": "my library panel"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "my-library-panel", element.UID)
+	assert.Equal(t, "text", element.Type)
+
+	elements, err := libraryElement.GetByName("my library panel")
+	assert.Nil(t, err)
+	assert.Len(t, elements, 1)
+
+	patchedElement, err := libraryElement.Patch("my-library-panel", &types.PatchLibraryElement{
+		Name:    "my new library panel",
+		Kind:    types.LibraryPanelKind,
+		Version: element.Version,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "my new library panel", patchedElement.Name)
+
+	searchResult, err := libraryElement.Search(QueryParameterLibraryElements{SearchString: "my new library", PerPage: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), searchResult.TotalCount)
+
+	connections, err := libraryElement.GetConnections("my-library-panel")
+	assert.Nil(t, err)
+	assert.Empty(t, connections)
+
+	// clean test
+	removeLibraryElement(t, "my-library-panel")
+}
+
+func initLibraryElementTest(t *testing.T) LibraryElementInterface {
+	httpClient, err := getRestClientWithBasicAuth()
+	assert.Nil(t, err)
+	return newLibraryElement(httpClient)
+}
+
+func removeLibraryElement(t *testing.T, uids ...string) {
+	libraryElementClient := initLibraryElementTest(t)
+	for _, uid := range uids {
+		libraryElementClient.Delete(uid) // nolint: errcheck
+	}
+}
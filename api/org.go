@@ -36,6 +36,9 @@ type CurrentOrgInterface interface {
 	RevokeInvite(int64) error
 	GetPreferences() (*types.OrgPrefs, error)
 	UpdatePreferences(*types.OrgPrefs) error
+	PatchPreferences(*types.PatchPreferences) error
+	// SetHomeDashboard sets the home dashboard of the organisation. An empty uid clears it.
+	SetHomeDashboard(uid string) error
 }
 
 func newCurrentOrg(client *grafanahttp.RESTClient) CurrentOrgInterface {
@@ -164,3 +167,15 @@ func (c *currentOrg) UpdatePreferences(pref *types.OrgPrefs) error {
 		Do().
 		Error()
 }
+
+func (c *currentOrg) PatchPreferences(pref *types.PatchPreferences) error {
+	return c.client.Patch(currentOrgAPI).
+		SetSubPath("/preferences").
+		Body(pref).
+		Do().
+		Error()
+}
+
+func (c *currentOrg) SetHomeDashboard(uid string) error {
+	return c.PatchPreferences(&types.PatchPreferences{HomeDashboardUID: &uid})
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentOrg_SetHomeDashboard(t *testing.T) {
+	testSuites := []struct {
+		title        string
+		uid          string
+		expectedBody string
+	}{
+		{
+			title:        "set the home dashboard",
+			uid:          "home-uid",
+			expectedBody: `{"homeDashboardUID":"home-uid"}`,
+		},
+		{
+			title:        "empty uid clears the home dashboard",
+			uid:          "",
+			expectedBody: `{"homeDashboardUID":""}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPatch, r.Method)
+				assert.Equal(t, "/api/org/preferences", r.URL.Path)
+				body, err := ioutil.ReadAll(r.Body)
+				assert.Nil(t, err)
+				assert.JSONEq(t, test.expectedBody, string(body))
+				_, _ = w.Write([]byte(`{"message": "Preferences updated"}`))
+			}))
+			defer server.Close()
+
+			rest, err := grafanahttp.NewWithURL(server.URL)
+			assert.Nil(t, err)
+			assert.Nil(t, newCurrentOrg(rest).SetHomeDashboard(test.uid))
+		})
+	}
+}
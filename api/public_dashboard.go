@@ -0,0 +1,201 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+const (
+	publicDashboardSubPath     = "/uid/:dashboardUID/public-dashboards"
+	publicDashboardListSubPath = "/public-dashboards"
+	// publicDashboardsDefaultPerPage is the page size used by ListAll when the query doesn't set it
+	publicDashboardsDefaultPerPage = 100
+)
+
+// PublicDashboardInterface manages the public configuration of the dashboards.
+// A dashboard has at most one public configuration, that's why the methods are keyed by the UID of the dashboard.
+type PublicDashboardInterface interface {
+	// InOrg returns a PublicDashboardInterface running every request in the given organisation.
+	// The user must be a member of the organisation.
+	InOrg(orgID int64) PublicDashboardInterface
+	List(QueryParameterPublicDashboards) (*types.PublicDashboardList, error)
+	// ListAll walks through every page and returns all public dashboards of the organisation.
+	ListAll() ([]*types.PublicDashboardListItem, error)
+	// Get returns the public configuration of the dashboard
+	Get(dashboardUID string) (*types.PublicDashboard, error)
+	Create(dashboardUID string, config *types.SavePublicDashboard) (*types.PublicDashboard, error)
+	// Update changes only the fields set in the configuration
+	Update(dashboardUID string, config *types.SavePublicDashboard) (*types.PublicDashboard, error)
+	Enable(dashboardUID string) (*types.PublicDashboard, error)
+	Disable(dashboardUID string) (*types.PublicDashboard, error)
+	// Delete removes the public configuration of the dashboard, the access token is revoked.
+	Delete(dashboardUID string) error
+	// AuditAllOrgs returns the public dashboards of every organisation. It requires the Grafana admin permission.
+	AuditAllOrgs() ([]*types.OrgPublicDashboard, error)
+	// RevokeAllOrgs disables every enabled public dashboard of every organisation, or deletes all of them when
+	// deleteConfig is true. It returns the public dashboards revoked, even when an error occurred.
+	RevokeAllOrgs(deleteConfig bool) ([]*types.OrgPublicDashboard, error)
+}
+
+func newPublicDashboard(client *grafanahttp.RESTClient) PublicDashboardInterface {
+	return &publicDashboard{
+		client: client,
+	}
+}
+
+type publicDashboard struct {
+	PublicDashboardInterface
+	client *grafanahttp.RESTClient
+}
+
+func (c *publicDashboard) InOrg(orgID int64) PublicDashboardInterface {
+	return newPublicDashboard(c.client.WithOrgID(orgID))
+}
+
+func (c *publicDashboard) List(query QueryParameterPublicDashboards) (*types.PublicDashboardList, error) {
+	result := &types.PublicDashboardList{}
+	err := c.client.Get(dashboardAPI).
+		SetSubPath(publicDashboardListSubPath).
+		Query(&query).
+		Do().
+		SaveAsObj(result)
+
+	return result, err
+}
+
+func (c *publicDashboard) ListAll() ([]*types.PublicDashboardListItem, error) {
+	query := QueryParameterPublicDashboards{PerPage: publicDashboardsDefaultPerPage}
+	var result []*types.PublicDashboardListItem
+	for page := 1; ; page++ {
+		query.Page = page
+		list, err := c.List(query)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, list.PublicDashboards...)
+		if len(list.PublicDashboards) < query.PerPage || int64(len(result)) >= list.TotalCount {
+			return result, nil
+		}
+	}
+}
+
+func (c *publicDashboard) Get(dashboardUID string) (*types.PublicDashboard, error) {
+	result := &types.PublicDashboard{}
+	err := c.client.Get(dashboardAPI).
+		SetSubPath(publicDashboardSubPath).
+		SetPathParam("dashboardUID", dashboardUID).
+		Do().
+		SaveAsObj(result)
+
+	return result, err
+}
+
+func (c *publicDashboard) Create(dashboardUID string, config *types.SavePublicDashboard) (*types.PublicDashboard, error) {
+	result := &types.PublicDashboard{}
+	err := c.client.Post(dashboardAPI).
+		SetSubPath(publicDashboardSubPath).
+		SetPathParam("dashboardUID", dashboardUID).
+		Body(config).
+		Do().
+		SaveAsObj(result)
+
+	return result, err
+}
+
+func (c *publicDashboard) Update(dashboardUID string, config *types.SavePublicDashboard) (*types.PublicDashboard, error) {
+	current, err := c.Get(dashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.PublicDashboard{}
+	err = c.client.Patch(dashboardAPI).
+		SetSubPath(publicDashboardSubPath+"/:uid").
+		SetPathParam("dashboardUID", dashboardUID).
+		SetPathParam("uid", current.UID).
+		Body(config).
+		Do().
+		SaveAsObj(result)
+
+	return result, err
+}
+
+func (c *publicDashboard) Enable(dashboardUID string) (*types.PublicDashboard, error) {
+	enabled := true
+	return c.Update(dashboardUID, &types.SavePublicDashboard{IsEnabled: &enabled})
+}
+
+func (c *publicDashboard) Disable(dashboardUID string) (*types.PublicDashboard, error) {
+	enabled := false
+	return c.Update(dashboardUID, &types.SavePublicDashboard{IsEnabled: &enabled})
+}
+
+func (c *publicDashboard) Delete(dashboardUID string) error {
+	current, err := c.Get(dashboardUID)
+	if err != nil {
+		return err
+	}
+	return c.client.Delete(dashboardAPI).
+		SetSubPath(publicDashboardSubPath+"/:uid").
+		SetPathParam("dashboardUID", dashboardUID).
+		SetPathParam("uid", current.UID).
+		Do().
+		Error()
+}
+
+func (c *publicDashboard) AuditAllOrgs() ([]*types.OrgPublicDashboard, error) {
+	orgList, err := newOrgs(c.client).Search(QueryParameterOrgs{})
+	if err != nil {
+		return nil, err
+	}
+	var result []*types.OrgPublicDashboard
+	for _, org := range orgList {
+		publicDashboards, err := c.InOrg(org.ID).ListAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range publicDashboards {
+			result = append(result, &types.OrgPublicDashboard{
+				OrgID:                   org.ID,
+				OrgName:                 org.Name,
+				PublicDashboardListItem: *item,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (c *publicDashboard) RevokeAllOrgs(deleteConfig bool) ([]*types.OrgPublicDashboard, error) {
+	publicDashboards, err := c.AuditAllOrgs()
+	if err != nil {
+		return nil, err
+	}
+	var revoked []*types.OrgPublicDashboard
+	for _, item := range publicDashboards {
+		orgClient := c.InOrg(item.OrgID)
+		if deleteConfig {
+			err = orgClient.Delete(item.DashboardUID)
+		} else if item.IsEnabled {
+			_, err = orgClient.Disable(item.DashboardUID)
+		} else {
+			continue
+		}
+		if err != nil {
+			return revoked, err
+		}
+		revoked = append(revoked, item)
+	}
+	return revoked, nil
+}
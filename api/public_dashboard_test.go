@@ -0,0 +1,66 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicDashboard_Lifecycle(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	_, err := initDashboardTest(t).Create(&types.SaveDashboard{
+		Dashboard: types.DashboardModel{"uid": "my-public-dashboard", "title": "my public dashboard"},
+	})
+	assert.Nil(t, err)
+
+	publicDashboard := initPublicDashboardTest(t)
+	enabled := true
+	created, err := publicDashboard.Create("my-public-dashboard", &types.SavePublicDashboard{IsEnabled: &enabled})
+	assert.Nil(t, err)
+	assert.True(t, created.IsEnabled)
+	assert.NotEmpty(t, created.AccessToken)
+
+	disabled, err := publicDashboard.Disable("my-public-dashboard")
+	assert.Nil(t, err)
+	assert.False(t, disabled.IsEnabled)
+	assert.Equal(t, created.AccessToken, disabled.AccessToken)
+
+	audit, err := publicDashboard.AuditAllOrgs()
+	assert.Nil(t, err)
+	assert.Len(t, audit, 1)
+	assert.Equal(t, "my-public-dashboard", audit[0].DashboardUID)
+	assert.Equal(t, int64(1), audit[0].OrgID)
+
+	assert.Nil(t, publicDashboard.Delete("my-public-dashboard"))
+	list, err := publicDashboard.ListAll()
+	assert.Nil(t, err)
+	assert.Empty(t, list)
+
+	// clean test
+	removeDashboard(t, "my-public-dashboard")
+}
+
+func initPublicDashboardTest(t *testing.T) PublicDashboardInterface {
+	httpClient, err := getRestClientWithBasicAuth()
+	assert.Nil(t, err)
+	return newPublicDashboard(httpClient)
+}
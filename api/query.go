@@ -16,6 +16,8 @@ package api
 import (
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/nexucis/grafana-go-client/api/types"
 	"github.com/nexucis/grafana-go-client/grafanahttp"
@@ -264,10 +266,21 @@ type QueryParameterSearch struct {
 	DashboardIDs []int64
 	// List of folder id’s to search in for dashboards
 	FolderIDs []int64
-	// Flag indicating if only starred Dashboards should be returned
-	Starred    bool
+	// List of dashboard uid's to search
+	DashboardUIDs []string
+	// List of folder uid's to search in for dashboards. Use types.GeneralFolderUID for the General folder.
+	FolderUIDs []string
+	// Flag indicating if only starred Dashboards should be returned. The filter isn't sent when it's false.
+	Starred bool
+	// Limit is the maximum number of results per page
 	Limit      int
 	Permission types.PermissionTypeAsString
+	// Sort orders the results, like alpha-asc. The results are sorted by title when it's empty.
+	Sort types.SearchSort
+	// Page to return, starting at 1. It's used with Limit to paginate the results.
+	Page int
+	// Deleted returns only the dashboards that are deleted and can still be restored (Grafana >= 11.3)
+	Deleted bool
 }
 
 func (q *QueryParameterSearch) GetValues() url.Values {
@@ -297,7 +310,17 @@ func (q *QueryParameterSearch) GetValues() url.Values {
 		}
 	}
 
-	values["starred"] = append(values["starred"], strconv.FormatBool(q.Starred))
+	if len(q.DashboardUIDs) > 0 {
+		values["dashboardUIDs"] = append(values["dashboardUIDs"], q.DashboardUIDs...)
+	}
+
+	if len(q.FolderUIDs) > 0 {
+		values["folderUIDs"] = append(values["folderUIDs"], q.FolderUIDs...)
+	}
+
+	if q.Starred {
+		values["starred"] = append(values["starred"], "true")
+	}
 
 	if q.Limit > 0 {
 		values["limit"] = append(values["limit"], strconv.Itoa(q.Limit))
@@ -307,5 +330,236 @@ func (q *QueryParameterSearch) GetValues() url.Values {
 		values["permission"] = append(values["permission"], string(q.Permission))
 	}
 
+	if len(q.Sort) > 0 {
+		values["sort"] = append(values["sort"], string(q.Sort))
+	}
+
+	if q.Page > 0 {
+		values["page"] = append(values["page"], strconv.Itoa(q.Page))
+	}
+
+	if q.Deleted {
+		values["deleted"] = append(values["deleted"], "true")
+	}
+
+	return values
+}
+
+type QueryParameterLibraryElements struct {
+	grafanahttp.QueryInterface
+	// Search the elements having a name or a description like this value
+	SearchString string
+	// Kind of the elements to return. When it's not set, panels and variables are returned.
+	Kind          types.LibraryElementKind
+	SortDirection types.LibraryElementSortDirection
+	// List of panel types to search
+	TypeFilter []string
+	// Element to exclude from the result
+	ExcludeUID string
+	// List of folder id's to search in. The General folder is the id 0.
+	FolderFilter []int64
+	// List of folder uid's to search in.
+	FolderFilterUIDs []string
+	// Number of elements per page. Grafana uses 100 when it's not set.
+	PerPage int
+	// Page to return, starting at 1
+	Page int
+}
+
+func (q *QueryParameterLibraryElements) GetValues() url.Values {
+	values := make(url.Values)
+
+	if len(q.SearchString) > 0 {
+		values["searchString"] = append(values["searchString"], q.SearchString)
+	}
+
+	if q.Kind > 0 {
+		values["kind"] = append(values["kind"], strconv.Itoa(int(q.Kind)))
+	}
+
+	if len(q.SortDirection) > 0 {
+		values["sortDirection"] = append(values["sortDirection"], string(q.SortDirection))
+	}
+
+	if len(q.TypeFilter) > 0 {
+		values["typeFilter"] = append(values["typeFilter"], strings.Join(q.TypeFilter, ","))
+	}
+
+	if len(q.ExcludeUID) > 0 {
+		values["excludeUid"] = append(values["excludeUid"], q.ExcludeUID)
+	}
+
+	if len(q.FolderFilter) > 0 {
+		ids := make([]string, 0, len(q.FolderFilter))
+		for _, id := range q.FolderFilter {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		values["folderFilter"] = append(values["folderFilter"], strings.Join(ids, ","))
+	}
+
+	if len(q.FolderFilterUIDs) > 0 {
+		values["folderFilterUIDs"] = append(values["folderFilterUIDs"], strings.Join(q.FolderFilterUIDs, ","))
+	}
+
+	if q.PerPage > 0 {
+		values["perPage"] = append(values["perPage"], strconv.Itoa(q.PerPage))
+	}
+
+	if q.Page > 0 {
+		values["page"] = append(values["page"], strconv.Itoa(q.Page))
+	}
+
+	return values
+}
+
+type QueryParameterDashboardVersions struct {
+	grafanahttp.QueryInterface
+	// Limit is the maximum number of versions to return
+	Limit int
+	// Start is the number of versions to skip, the most recent ones being skipped first. Grafana >= 11 ignores it
+	// when it pages with a continue token.
+	Start int
+	// ContinueToken is the token of the previous page, to get the next one (Grafana >= 11)
+	ContinueToken string
+}
+
+func (q *QueryParameterDashboardVersions) GetValues() url.Values {
+	values := make(url.Values)
+
+	if q.Limit > 0 {
+		values["limit"] = append(values["limit"], strconv.Itoa(q.Limit))
+	}
+
+	if q.Start > 0 {
+		values["start"] = append(values["start"], strconv.Itoa(q.Start))
+	}
+
+	if len(q.ContinueToken) > 0 {
+		values["continueToken"] = append(values["continueToken"], q.ContinueToken)
+	}
+
+	return values
+}
+
+type QueryParameterFolders struct {
+	grafanahttp.QueryInterface
+	// ParentUID lists the subfolders of this folder instead of the folders at the root (Grafana >= 10)
+	ParentUID string
+	// Limit is the maximum number of folders per page
+	Limit int
+	// Page to return, starting at 1
+	Page int
+}
+
+func (q *QueryParameterFolders) GetValues() url.Values {
+	values := make(url.Values)
+
+	if len(q.ParentUID) > 0 {
+		values["parentUid"] = append(values["parentUid"], q.ParentUID)
+	}
+
+	if q.Limit > 0 {
+		values["limit"] = append(values["limit"], strconv.Itoa(q.Limit))
+	}
+
+	if q.Page > 0 {
+		values["page"] = append(values["page"], strconv.Itoa(q.Page))
+	}
+
+	return values
+}
+
+type QueryParameterPublicDashboards struct {
+	grafanahttp.QueryInterface
+	// Page to return, starting at 1
+	Page int
+	// Number of public dashboards per page
+	PerPage int
+}
+
+func (q *QueryParameterPublicDashboards) GetValues() url.Values {
+	values := make(url.Values)
+
+	if q.Page > 0 {
+		values["page"] = append(values["page"], strconv.Itoa(q.Page))
+	}
+
+	if q.PerPage > 0 {
+		values["perpage"] = append(values["perpage"], strconv.Itoa(q.PerPage))
+	}
+
+	return values
+}
+
+type QueryParameterRender struct {
+	grafanahttp.QueryInterface
+	OrgID int64
+	// PanelID is the panel to render. It's required to render a single panel.
+	PanelID int64
+	// Width and Height are the size of the image in pixels. Grafana uses 800x400 when they are not set.
+	Width  int
+	Height int
+	// Scale is the device scale factor, 2 produces an image twice bigger with a better resolution
+	Scale float64
+	// Theme is either light or dark
+	Theme string
+	// From and To are the time range, either relative like now-6h or an epoch in milliseconds
+	From string
+	To   string
+	// Variables contains the values of the template variables, by variable name
+	Variables map[string][]string
+	// Timezone is used to display the dates, like UTC or Europe/Paris
+	Timezone string
+	// Timeout is the maximum time the renderer can take. It's rounded up to the second.
+	Timeout time.Duration
+}
+
+func (q *QueryParameterRender) GetValues() url.Values {
+	values := make(url.Values)
+
+	if q.OrgID > 0 {
+		values["orgId"] = append(values["orgId"], strconv.FormatInt(q.OrgID, 10))
+	}
+
+	if q.PanelID > 0 {
+		values["panelId"] = append(values["panelId"], strconv.FormatInt(q.PanelID, 10))
+	}
+
+	if q.Width > 0 {
+		values["width"] = append(values["width"], strconv.Itoa(q.Width))
+	}
+
+	if q.Height > 0 {
+		values["height"] = append(values["height"], strconv.Itoa(q.Height))
+	}
+
+	if q.Scale > 0 {
+		values["scale"] = append(values["scale"], strconv.FormatFloat(q.Scale, 'f', -1, 64))
+	}
+
+	if len(q.Theme) > 0 {
+		values["theme"] = append(values["theme"], q.Theme)
+	}
+
+	if len(q.From) > 0 {
+		values["from"] = append(values["from"], q.From)
+	}
+
+	if len(q.To) > 0 {
+		values["to"] = append(values["to"], q.To)
+	}
+
+	for name, variableValues := range q.Variables {
+		values["var-"+name] = append(values["var-"+name], variableValues...)
+	}
+
+	if len(q.Timezone) > 0 {
+		values["tz"] = append(values["tz"], q.Timezone)
+	}
+
+	if q.Timeout > 0 {
+		values["timeout"] = append(values["timeout"], strconv.FormatInt(int64((q.Timeout+time.Second-1)/time.Second), 10))
+	}
+
 	return values
 }
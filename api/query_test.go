@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/nexucis/grafana-go-client/api/types"
 	"github.com/stretchr/testify/assert"
@@ -238,10 +239,203 @@ func TestQueryParameterSearch_GetValues(t *testing.T) {
 				"type":         []string{"dash-db"},
 				"dashboardIds": []string{"56432", "156584"},
 				"folderIds":    []string{"79665", "112321", "887543"},
-				"starred":      []string{"false"},
 				"permission":   []string{"Edit"},
 			},
 		},
+		{
+			title: "test with uids, pagination and starred",
+			query: &QueryParameterSearch{
+				DashboardUIDs: []string{"abc", "def"},
+				FolderUIDs:    []string{types.GeneralFolderUID, "ghi"},
+				Starred:       true,
+				Sort:          types.SearchSortAlphaDesc,
+				Page:          3,
+				Limit:         100,
+				Deleted:       true,
+			},
+			result: url.Values{
+				"dashboardUIDs": []string{"abc", "def"},
+				"folderUIDs":    []string{"general", "ghi"},
+				"starred":       []string{"true"},
+				"sort":          []string{"alpha-desc"},
+				"page":          []string{"3"},
+				"limit":         []string{"100"},
+				"deleted":       []string{"true"},
+			},
+		},
+	}
+
+	for _, testSuite := range testSuites {
+		assert.Equal(t, testSuite.result, testSuite.query.GetValues(), fmt.Sprintf("error in test %s", testSuite.title))
+	}
+}
+
+func TestQueryParameterDashboardVersions_GetValues(t *testing.T) {
+	testSuites := []struct {
+		title  string
+		query  *QueryParameterDashboardVersions
+		result url.Values
+	}{
+		{
+			title:  "test with no parameter",
+			query:  &QueryParameterDashboardVersions{},
+			result: url.Values{},
+		},
+		{
+			title: "test with all parameter",
+			query: &QueryParameterDashboardVersions{Limit: 10, Start: 20, ContinueToken: "next-page"},
+			result: url.Values{
+				"limit":         []string{"10"},
+				"start":         []string{"20"},
+				"continueToken": []string{"next-page"},
+			},
+		},
+	}
+
+	for _, testSuite := range testSuites {
+		assert.Equal(t, testSuite.result, testSuite.query.GetValues(), fmt.Sprintf("error in test %s", testSuite.title))
+	}
+}
+
+func TestQueryParameterFolders_GetValues(t *testing.T) {
+	testSuites := []struct {
+		title  string
+		query  *QueryParameterFolders
+		result url.Values
+	}{
+		{
+			title:  "test with no parameter",
+			query:  &QueryParameterFolders{},
+			result: url.Values{},
+		},
+		{
+			title: "test with all parameter",
+			query: &QueryParameterFolders{ParentUID: "parent", Limit: 50, Page: 2},
+			result: url.Values{
+				"parentUid": []string{"parent"},
+				"limit":     []string{"50"},
+				"page":      []string{"2"},
+			},
+		},
+	}
+
+	for _, testSuite := range testSuites {
+		assert.Equal(t, testSuite.result, testSuite.query.GetValues(), fmt.Sprintf("error in test %s", testSuite.title))
+	}
+}
+
+func TestQueryParameterLibraryElements_GetValues(t *testing.T) {
+	testSuites := []struct {
+		title  string
+		query  *QueryParameterLibraryElements
+		result url.Values
+	}{
+		{
+			title:  "test with no parameter",
+			query:  &QueryParameterLibraryElements{},
+			result: url.Values{},
+		},
+		{
+			title: "test with all parameter",
+			query: &QueryParameterLibraryElements{
+				SearchString:     "cpu",
+				Kind:             types.LibraryPanelKind,
+				SortDirection:    types.LibraryElementSortAlphaDesc,
+				TypeFilter:       []string{"timeseries", "stat"},
+				ExcludeUID:       "abcd",
+				FolderFilter:     []int64{0, 12},
+				FolderFilterUIDs: []string{"folder-a", "folder-b"},
+				PerPage:          20,
+				Page:             3,
+			},
+			result: url.Values{
+				"searchString":     []string{"cpu"},
+				"kind":             []string{"1"},
+				"sortDirection":    []string{"alpha-desc"},
+				"typeFilter":       []string{"timeseries,stat"},
+				"excludeUid":       []string{"abcd"},
+				"folderFilter":     []string{"0,12"},
+				"folderFilterUIDs": []string{"folder-a,folder-b"},
+				"perPage":          []string{"20"},
+				"page":             []string{"3"},
+			},
+		},
+	}
+
+	for _, testSuite := range testSuites {
+		assert.Equal(t, testSuite.result, testSuite.query.GetValues(), fmt.Sprintf("error in test %s", testSuite.title))
+	}
+}
+
+func TestQueryParameterPublicDashboards_GetValues(t *testing.T) {
+	testSuites := []struct {
+		title  string
+		query  *QueryParameterPublicDashboards
+		result url.Values
+	}{
+		{
+			title:  "test with no parameter",
+			query:  &QueryParameterPublicDashboards{},
+			result: url.Values{},
+		},
+		{
+			title: "test with all parameter",
+			query: &QueryParameterPublicDashboards{
+				Page:    2,
+				PerPage: 50,
+			},
+			result: url.Values{
+				"page":    []string{"2"},
+				"perpage": []string{"50"},
+			},
+		},
+	}
+
+	for _, testSuite := range testSuites {
+		assert.Equal(t, testSuite.result, testSuite.query.GetValues(), fmt.Sprintf("error in test %s", testSuite.title))
+	}
+}
+
+func TestQueryParameterRender_GetValues(t *testing.T) {
+	testSuites := []struct {
+		title  string
+		query  *QueryParameterRender
+		result url.Values
+	}{
+		{
+			title:  "test with no parameter",
+			query:  &QueryParameterRender{},
+			result: url.Values{},
+		},
+		{
+			title: "test with all parameter",
+			query: &QueryParameterRender{
+				OrgID:     1,
+				PanelID:   4,
+				Width:     1000,
+				Height:    500,
+				Scale:     1.5,
+				Theme:     "light",
+				From:      "now-1h",
+				To:        "now",
+				Variables: map[string][]string{"job": {"node", "api"}},
+				Timezone:  "UTC",
+				Timeout:   1500 * time.Millisecond,
+			},
+			result: url.Values{
+				"orgId":   []string{"1"},
+				"panelId": []string{"4"},
+				"width":   []string{"1000"},
+				"height":  []string{"500"},
+				"scale":   []string{"1.5"},
+				"theme":   []string{"light"},
+				"from":    []string{"now-1h"},
+				"to":      []string{"now"},
+				"var-job": []string{"node", "api"},
+				"tz":      []string{"UTC"},
+				"timeout": []string{"2"},
+			},
+		},
 	}
 
 	for _, testSuite := range testSuites {
@@ -0,0 +1,134 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+const (
+	renderAPI           = "/render"
+	frontendSettingsAPI = "/api/frontend/settings"
+	pngContentType      = "image/png"
+)
+
+// RendererNotInstalledError is returned when Grafana can't render an image because no image renderer is available
+type RendererNotInstalledError struct {
+	// Err is the error returned by Grafana, it's nil when the absence of renderer is known before the rendering
+	Err error
+}
+
+func (e *RendererNotInstalledError) Error() string {
+	message := "no image renderer is installed on Grafana"
+	if e.Err != nil {
+		message = message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *RendererNotInstalledError) Unwrap() error {
+	return e.Err
+}
+
+// RenderInterface renders dashboards and panels as PNG images.
+// Before the first rendering, it checks that a renderer is installed. The check is done only once
+// for a given ClientInterface.
+type RenderInterface interface {
+	// IsAvailable returns true when an image renderer is installed.
+	// Grafana older than 7.0 doesn't provide this information, in this case the renderer is considered as available.
+	IsAvailable() (bool, error)
+	// Dashboard writes the PNG image of the whole dashboard into the writer.
+	// The field PanelID of the query is ignored.
+	Dashboard(uid string, query QueryParameterRender, w io.Writer) error
+	// Panel writes the PNG image of a single panel into the writer. The field PanelID of the query is required.
+	Panel(uid string, query QueryParameterRender, w io.Writer) error
+}
+
+// errMissingPanelID is returned by RenderInterface.Panel when the panel to render is not set
+var errMissingPanelID = errors.New("the panel to render is required")
+
+func newRender(client *grafanahttp.RESTClient) RenderInterface {
+	return &render{
+		client: client,
+	}
+}
+
+type render struct {
+	RenderInterface
+	client *grafanahttp.RESTClient
+	// rendererFound is set to 1 once a renderer has been found, to avoid checking it before every rendering
+	rendererFound int32
+}
+
+func (c *render) IsAvailable() (bool, error) {
+	result := &struct {
+		RendererAvailable *bool `json:"rendererAvailable"`
+	}{}
+	err := c.client.Get(frontendSettingsAPI).
+		Do().
+		SaveAsObj(result)
+	if err != nil {
+		return false, err
+	}
+	return result.RendererAvailable == nil || *result.RendererAvailable, nil
+}
+
+func (c *render) Dashboard(uid string, query QueryParameterRender, w io.Writer) error {
+	query.PanelID = 0
+	return c.render("/d/:uid", uid, query, w)
+}
+
+func (c *render) Panel(uid string, query QueryParameterRender, w io.Writer) error {
+	if query.PanelID == 0 {
+		return errMissingPanelID
+	}
+	return c.render("/d-solo/:uid", uid, query, w)
+}
+
+func (c *render) render(subPath string, uid string, query QueryParameterRender, w io.Writer) error {
+	// when there is no renderer, Grafana returns an image saying that the renderer is not installed instead of an error.
+	// A renderer doesn't disappear, so once it has been found, it's not checked anymore.
+	if atomic.LoadInt32(&c.rendererFound) == 0 {
+		available, err := c.IsAvailable()
+		if err != nil {
+			return err
+		}
+		if !available {
+			return &RendererNotInstalledError{}
+		}
+		atomic.StoreInt32(&c.rendererFound, 1)
+	}
+	err := c.client.Get(renderAPI).
+		SetSubPath(subPath).
+		SetPathParam("uid", uid).
+		SetHeader("Accept", pngContentType).
+		Query(&query).
+		Stream(w, pngContentType).
+		Error()
+	if requestErr, isRequestErr := err.(*grafanahttp.RequestError); isRequestErr && isRendererUnavailable(requestErr) {
+		return &RendererNotInstalledError{Err: err}
+	}
+	return err
+}
+
+// isRendererUnavailable returns true when the error sent by Grafana says that the renderer is missing or not reachable
+func isRendererUnavailable(err *grafanahttp.RequestError) bool {
+	message := strings.ToLower(err.Message)
+	return strings.Contains(message, "not available") || strings.Contains(message, "not installed")
+}
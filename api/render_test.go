@@ -0,0 +1,110 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender_Panel(t *testing.T) {
+	if !*integration {
+		// test is ignored
+		t.Log("test is ignored")
+		return
+	}
+
+	_, err := initDashboardTest(t).Create(&types.SaveDashboard{
+		Dashboard: types.DashboardModel{"uid": "my-dashboard", "title": "my dashboard", "panels": []interface{}{
+			map[string]interface{}{"id": 1, "type": "text", "title": "my panel"},
+		}},
+	})
+	assert.Nil(t, err)
+
+	httpClient, err := getRestClientWithBasicAuth()
+	assert.Nil(t, err)
+	render := newRender(httpClient)
+	available, err := render.IsAvailable()
+	assert.Nil(t, err)
+
+	image := &bytes.Buffer{}
+	err = render.Panel("my-dashboard", QueryParameterRender{PanelID: 1, Width: 300, Height: 200}, image)
+	if available {
+		assert.Nil(t, err)
+		assert.True(t, bytes.HasPrefix(image.Bytes(), []byte("\x89PNG")))
+	} else {
+		assert.IsType(t, &RendererNotInstalledError{}, err)
+		assert.Zero(t, image.Len())
+	}
+
+	// clean test
+	removeDashboard(t, "my-dashboard")
+}
+
+func TestRender_PanelChecksRendererOnce(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path == frontendSettingsAPI {
+			_, _ = w.Write([]byte(`{"rendererAvailable": true}`))
+			return
+		}
+		assert.Equal(t, "2", r.URL.Query().Get("panelId"))
+		w.Header().Set("Content-Type", pngContentType)
+		_, _ = w.Write([]byte("\x89PNG"))
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	// the renderer is checked once for the client, even when Render is called for every image
+	client := NewWithClient(rest)
+	for i := 0; i < 2; i++ {
+		image := &bytes.Buffer{}
+		assert.Nil(t, client.Render().Panel("abcd", QueryParameterRender{PanelID: 2}, image))
+		assert.Equal(t, "\x89PNG", image.String())
+	}
+	assert.Equal(t, []string{frontendSettingsAPI, "/render/d-solo/abcd", "/render/d-solo/abcd"}, requests)
+}
+
+func TestRender_RendererNotInstalled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, frontendSettingsAPI, r.URL.Path)
+		_, _ = w.Write([]byte(`{"rendererAvailable": false}`))
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	err = newRender(rest).Dashboard("abcd", QueryParameterRender{}, &bytes.Buffer{})
+	assert.IsType(t, &RendererNotInstalledError{}, err)
+}
+
+func TestRender_PanelWithoutID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	err = newRender(rest).Panel("abcd", QueryParameterRender{Width: 300}, &bytes.Buffer{})
+	assert.Equal(t, errMissingPanelID, err)
+}
@@ -18,10 +18,23 @@ import (
 	"github.com/nexucis/grafana-go-client/grafanahttp"
 )
 
-const searchAPI = "/api/search"
+const (
+	searchAPI = "/api/search"
+	// searchDefaultLimit is the page size used by QueryAll and Iterate when the query doesn't set the limit
+	searchDefaultLimit = 1000
+	// searchMaxLimit is the maximum number of results Grafana returns in a page, whatever the limit requested
+	searchMaxLimit = 5000
+)
 
 type SearchInterface interface {
+	// Query returns a single page of results. Without limit, Grafana returns at most 1000 results
+	// and it never returns more than 5000 results.
 	Query(QueryParameterSearch) ([]*types.SearchResult, error)
+	// QueryAll walks through every page, starting at query.Page, and returns all results matching the query.
+	QueryAll(QueryParameterSearch) ([]*types.SearchResult, error)
+	// Iterate returns an iterator walking through every page, starting at query.Page.
+	// A page is requested only once the results of the previous one are consumed.
+	Iterate(QueryParameterSearch) *SearchIterator
 }
 
 func newSearch(client *grafanahttp.RESTClient) SearchInterface {
@@ -43,3 +56,74 @@ func (c *search) Query(query QueryParameterSearch) ([]*types.SearchResult, error
 		SaveAsObj(&response)
 	return response, err
 }
+
+func (c *search) QueryAll(query QueryParameterSearch) ([]*types.SearchResult, error) {
+	var result []*types.SearchResult
+	it := c.Iterate(query)
+	for it.Next() {
+		result = append(result, it.Result())
+	}
+	return result, it.Err()
+}
+
+func (c *search) Iterate(query QueryParameterSearch) *SearchIterator {
+	if query.Limit <= 0 {
+		query.Limit = searchDefaultLimit
+	}
+	// a bigger limit would make the first page look like the last one since it can't be full
+	if query.Limit > searchMaxLimit {
+		query.Limit = searchMaxLimit
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	return &SearchIterator{search: c, query: query}
+}
+
+// SearchIterator walks through the pages of a search. Use it like this:
+//
+//	it := client.Search().Iterate(query)
+//	for it.Next() {
+//		hit := it.Result()
+//	}
+//	err := it.Err()
+type SearchIterator struct {
+	search  SearchInterface
+	query   QueryParameterSearch
+	page    []*types.SearchResult
+	current *types.SearchResult
+	done    bool
+	err     error
+}
+
+// Next moves to the next result, requesting the next page if needed.
+// It returns false when there is no more result or when a request failed.
+func (it *SearchIterator) Next() bool {
+	if len(it.page) == 0 {
+		if it.done || it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.page, it.err = it.search.Query(it.query)
+		if it.err != nil || len(it.page) == 0 {
+			it.current = nil
+			return false
+		}
+		// a page not full is the last one
+		it.done = len(it.page) < it.query.Limit
+		it.query.Page++
+	}
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Result returns the current result
+func (it *SearchIterator) Result() *types.SearchResult {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *SearchIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch_QueryAll(t *testing.T) {
+	var requestedPages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPages = append(requestedPages, r.URL.Query().Get("page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var hits []*types.SearchResult
+		// 5 results in total
+		for i := (page - 1) * limit; i < page*limit && i < 5; i++ {
+			hits = append(hits, &types.SearchResult{UID: strconv.Itoa(i)})
+		}
+		if hits == nil {
+			hits = []*types.SearchResult{}
+		}
+		_ = json.NewEncoder(w).Encode(hits)
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	search := newSearch(rest)
+
+	hits, err := search.QueryAll(QueryParameterSearch{Limit: 2})
+	assert.Nil(t, err)
+	var uids []string
+	for _, hit := range hits {
+		uids = append(uids, hit.UID)
+	}
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, uids)
+	assert.Equal(t, []string{"1", "2", "3"}, requestedPages)
+
+	// when the last page is full, an empty page ends the iteration
+	requestedPages = nil
+	hits, err = search.QueryAll(QueryParameterSearch{Limit: 5})
+	assert.Nil(t, err)
+	assert.Len(t, hits, 5)
+	assert.Equal(t, []string{"1", "2"}, requestedPages)
+}
+
+func TestSearch_QueryAll_LimitAboveServerCap(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query().Get("page")+"/"+r.URL.Query().Get("limit"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		// like Grafana, the limit is capped
+		if limit > 5000 {
+			limit = 5000
+		}
+		hits := []*types.SearchResult{}
+		// 12000 results in total
+		for i := (page - 1) * limit; i < page*limit && i < 12000; i++ {
+			hits = append(hits, &types.SearchResult{UID: strconv.Itoa(i)})
+		}
+		_ = json.NewEncoder(w).Encode(hits)
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	hits, err := newSearch(rest).QueryAll(QueryParameterSearch{Limit: 10000})
+	assert.Nil(t, err)
+	assert.Len(t, hits, 12000)
+	assert.Equal(t, "11999", hits[11999].UID)
+	assert.Equal(t, []string{"1/5000", "2/5000", "3/5000"}, requests)
+}
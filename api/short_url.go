@@ -0,0 +1,57 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+const shortURLAPI = "/api/short-urls"
+
+type ShortURLInterface interface {
+	// Create returns a short link to the path. The path is relative to the root of Grafana, like d/:uid/:slug?orgId=1.
+	// See dashboard.URL and dashboard.ExploreURL to build the path and to shorten it directly.
+	Create(path string) (*types.ShortURL, error)
+}
+
+func newShortURL(client *grafanahttp.RESTClient) ShortURLInterface {
+	return &shortURL{
+		client: client,
+	}
+}
+
+type shortURL struct {
+	ShortURLInterface
+	client *grafanahttp.RESTClient
+}
+
+func (c *shortURL) Create(path string) (*types.ShortURL, error) {
+	body := struct {
+		Path string `json:"path" binding:"Required"`
+	}{
+		// Grafana rejects the absolute paths
+		Path: strings.TrimLeft(path, "/"),
+	}
+
+	result := &types.ShortURL{}
+	err := c.client.Post(shortURLAPI).
+		Body(body).
+		Do().
+		SaveAsObj(result)
+
+	return result, err
+}
@@ -31,6 +31,11 @@ type TeamInterface interface {
 	AddMembers(teamID int64, userID int64) error
 	Delete(teamID int64) error
 	DeleteMembers(teamID int64, userID int64) error
+	GetPreferences(teamID int64) (*types.TeamPrefs, error)
+	UpdatePreferences(teamID int64, pref *types.TeamPrefs) error
+	PatchPreferences(teamID int64, pref *types.PatchPreferences) error
+	// SetHomeDashboard sets the home dashboard of the team. An empty uid clears it.
+	SetHomeDashboard(teamID int64, uid string) error
 }
 
 func newTeam(client *grafanahttp.RESTClient) TeamInterface {
@@ -125,3 +130,35 @@ func (c *team) DeleteMembers(teamID int64, userID int64) error {
 		Do().
 		Error()
 }
+
+func (c *team) GetPreferences(teamID int64) (*types.TeamPrefs, error) {
+	response := &types.TeamPrefs{}
+	err := c.client.Get(teamAPI).
+		SetSubPath("/:teamId/preferences").
+		SetPathParam("teamId", strconv.FormatInt(teamID, 10)).
+		Do().
+		SaveAsObj(response)
+	return response, err
+}
+
+func (c *team) UpdatePreferences(teamID int64, pref *types.TeamPrefs) error {
+	return c.client.Put(teamAPI).
+		SetSubPath("/:teamId/preferences").
+		SetPathParam("teamId", strconv.FormatInt(teamID, 10)).
+		Body(pref).
+		Do().
+		Error()
+}
+
+func (c *team) PatchPreferences(teamID int64, pref *types.PatchPreferences) error {
+	return c.client.Patch(teamAPI).
+		SetSubPath("/:teamId/preferences").
+		SetPathParam("teamId", strconv.FormatInt(teamID, 10)).
+		Body(pref).
+		Do().
+		Error()
+}
+
+func (c *team) SetHomeDashboard(teamID int64, uid string) error {
+	return c.PatchPreferences(teamID, &types.PatchPreferences{HomeDashboardUID: &uid})
+}
@@ -14,9 +14,13 @@
 package api
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -224,3 +228,39 @@ func removeTeam(t *testing.T, ids ...int64) {
 		teamClient.Delete(id) // nolint: errcheck
 	}
 }
+
+func TestTeam_SetHomeDashboard(t *testing.T) {
+	testSuites := []struct {
+		title        string
+		uid          string
+		expectedBody string
+	}{
+		{
+			title:        "set the home dashboard",
+			uid:          "home-uid",
+			expectedBody: `{"homeDashboardUID":"home-uid"}`,
+		},
+		{
+			title:        "empty uid clears the home dashboard",
+			uid:          "",
+			expectedBody: `{"homeDashboardUID":""}`,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPatch, r.Method)
+				assert.Equal(t, "/api/teams/7/preferences", r.URL.Path)
+				body, err := ioutil.ReadAll(r.Body)
+				assert.Nil(t, err)
+				assert.JSONEq(t, test.expectedBody, string(body))
+				_, _ = w.Write([]byte(`{"message": "Preferences updated"}`))
+			}))
+			defer server.Close()
+
+			rest, err := grafanahttp.NewWithURL(server.URL)
+			assert.Nil(t, err)
+			assert.Nil(t, newTeam(rest).SetHomeDashboard(7, test.uid))
+		})
+	}
+}
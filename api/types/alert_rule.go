@@ -0,0 +1,55 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+const (
+	// ExpressionDatasourceUID is the datasource of the queries of an alert rule that are server-side expressions
+	ExpressionDatasourceUID = "__expr__"
+	// AlertRuleDashboardUIDAnnotation and AlertRulePanelIDAnnotation link an alert rule to a panel
+	AlertRuleDashboardUIDAnnotation = "__dashboardUid__"
+	AlertRulePanelIDAnnotation      = "__panelId__"
+)
+
+// AlertRule is a Grafana-managed alert rule, also known as a unified alert rule
+type AlertRule struct {
+	ID        int64  `json:"id"`
+	UID       string `json:"uid"`
+	OrgID     int64  `json:"orgID"`
+	FolderUID string `json:"folderUID"`
+	RuleGroup string `json:"ruleGroup"`
+	Title     string `json:"title"`
+	// Condition is the refId of the query or the expression deciding if the rule fires
+	Condition    string            `json:"condition"`
+	Data         []*AlertQuery     `json:"data"`
+	NoDataState  string            `json:"noDataState"`
+	ExecErrState string            `json:"execErrState"`
+	For          string            `json:"for"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	IsPaused     bool              `json:"isPaused"`
+}
+
+type AlertQuery struct {
+	RefID             string                 `json:"refId"`
+	QueryType         string                 `json:"queryType,omitempty"`
+	RelativeTimeRange *RelativeTimeRange     `json:"relativeTimeRange,omitempty"`
+	DatasourceUID     string                 `json:"datasourceUid"`
+	Model             map[string]interface{} `json:"model"`
+}
+
+// RelativeTimeRange is the time range of a query, in seconds before the evaluation
+type RelativeTimeRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
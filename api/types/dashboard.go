@@ -14,6 +14,7 @@
 package types
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -34,9 +35,106 @@ type Dashboard struct {
 	Title   string `json:"title"`
 }
 
+// DashboardModel is the JSON model of a dashboard.
+// It's kept as a generic map so that no field is lost when a dashboard is read and then saved again.
+type DashboardModel map[string]interface{}
+
+// UID returns the unique identifier of the dashboard or an empty string if it's not set.
+func (d DashboardModel) UID() string {
+	uid, _ := d["uid"].(string)
+	return uid
+}
+
+// Title returns the title of the dashboard or an empty string if it's not set.
+func (d DashboardModel) Title() string {
+	title, _ := d["title"].(string)
+	return title
+}
+
+// Version returns the version of the dashboard or 0 if it's not set.
+func (d DashboardModel) Version() int {
+	version, _ := d["version"].(float64)
+	return int(version)
+}
+
+type SaveDashboard struct {
+	Dashboard DashboardModel `json:"dashboard" binding:"Required"`
+	FolderID  int64          `json:"folderId"`
+	// FolderUID is supported since Grafana 8.0 and takes precedence over FolderID
+	FolderUID string `json:"folderUid,omitempty"`
+	// Message is the commit message stored with the new version of the dashboard
+	Message string `json:"message,omitempty"`
+	// Overwrite allows to save the dashboard even if its version or its title conflicts with an existing one
+	Overwrite bool `json:"overwrite"`
+}
+
 type DashboardWithMeta struct {
-	Meta      DashboardMeta `json:"meta"`
-	Dashboard interface{}   `json:"dashboard"`
+	Meta      DashboardMeta  `json:"meta"`
+	Dashboard DashboardModel `json:"dashboard"`
+}
+
+type HomeDashboard struct {
+	Meta      DashboardMeta  `json:"meta"`
+	Dashboard DashboardModel `json:"dashboard"`
+	// RedirectURI is set instead of the dashboard when the home dashboard is chosen by uid in the preferences (Grafana 9+)
+	RedirectURI string `json:"redirectUri,omitempty"`
+}
+
+// DashboardInput is an entry of the section __inputs of a dashboard exported for sharing externally.
+type DashboardInput struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	// Type is either "datasource" or "constant"
+	Type       string `json:"type"`
+	PluginID   string `json:"pluginId,omitempty"`
+	PluginName string `json:"pluginName,omitempty"`
+	Value      string `json:"value,omitempty"`
+}
+
+// DashboardRequirement is an entry of the section __requires of a dashboard exported for sharing externally.
+type DashboardRequirement struct {
+	// Type is either "grafana", "datasource" or "panel"
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type ImportDashboardInput struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	PluginID string `json:"pluginId,omitempty"`
+	// Value is the uid of the datasource for an input of type "datasource"
+	// or the value of the constant for an input of type "constant"
+	Value string `json:"value"`
+}
+
+type ImportDashboard struct {
+	Dashboard DashboardModel          `json:"dashboard"`
+	Overwrite bool                    `json:"overwrite"`
+	Inputs    []*ImportDashboardInput `json:"inputs"`
+	FolderID  int64                   `json:"folderId,omitempty"`
+	// FolderUID is supported since Grafana 8.0 and takes precedence over FolderID
+	FolderUID string `json:"folderUid,omitempty"`
+}
+
+type ImportDashboardResponse struct {
+	UID              string `json:"uid"`
+	PluginID         string `json:"pluginId"`
+	Title            string `json:"title"`
+	Imported         bool   `json:"imported"`
+	ImportedURI      string `json:"importedUri"`
+	ImportedURL      string `json:"importedUrl"`
+	Slug             string `json:"slug"`
+	DashboardID      int64  `json:"dashboardId"`
+	FolderID         int64  `json:"folderId"`
+	FolderUID        string `json:"folderUid"`
+	ImportedRevision int64  `json:"importedRevision"`
+	Revision         int64  `json:"revision"`
+	Description      string `json:"description"`
+	Path             string `json:"path"`
+	Removed          bool   `json:"removed"`
 }
 
 type DashboardTags struct {
@@ -56,6 +154,22 @@ type DashboardVersion struct {
 	Data          interface{} `json:"data"`
 }
 
+// DashboardVersionList is a page of versions of a dashboard.
+// Before Grafana 11, Grafana returns only the list of versions and the pages are requested with the start parameter.
+type DashboardVersionList struct {
+	// ContinueToken is used to request the next page. It's empty on the last page.
+	ContinueToken string              `json:"continueToken"`
+	Versions      []*DashboardVersion `json:"versions"`
+}
+
+func (l *DashboardVersionList) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &l.Versions)
+	}
+	type alias DashboardVersionList
+	return json.Unmarshal(data, (*alias)(l))
+}
+
 type DashboardMeta struct {
 	IsStarred   bool      `json:"isStarred,omitempty"`
 	IsHome      bool      `json:"isHome,omitempty"`
@@ -77,6 +191,54 @@ type DashboardMeta struct {
 	UpdatedBy   string    `json:"updatedBy"`
 	CreatedBy   string    `json:"createdBy"`
 	FolderID    int64     `json:"folderId"`
+	FolderUID   string    `json:"folderUid"`
 	FolderTitle string    `json:"folderTitle"`
 	FolderURL   string    `json:"folderUrl"`
 }
+
+// JSONPatchOperation is an operation of a JSON Patch as defined by the RFC 6902
+type JSONPatchOperation struct {
+	// Op is one of add, remove, replace, move, copy or test
+	Op string `json:"op"`
+	// Path is a JSON Pointer as defined by the RFC 6901, like /panels/0/title
+	Path string `json:"path"`
+	// From is used by the operations move and copy
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type DashboardPatch struct {
+	// JSONPatch is applied first. Operations "test" can be used as preconditions.
+	JSONPatch []JSONPatchOperation
+	// MergePatch is a JSON Merge Patch as defined by the RFC 7396. It's applied after JSONPatch.
+	MergePatch map[string]interface{}
+	// Message is the commit message stored with the new version of the dashboard
+	Message string
+	// DryRun allows to get the changes without saving the dashboard
+	DryRun bool
+}
+
+type DashboardPatchResult struct {
+	// Dashboard is the dashboard once patched
+	Dashboard DashboardModel
+	Changes   []*DashboardChange
+	// Saved is nil when it's a dry run or when the patch doesn't change anything
+	Saved *SimpleDashboard
+}
+
+type DashboardChangeType string
+
+const (
+	DashboardChangeAdded   DashboardChangeType = "added"
+	DashboardChangeRemoved DashboardChangeType = "removed"
+	DashboardChangeUpdated DashboardChangeType = "updated"
+)
+
+// DashboardChange is a difference between two versions of a dashboard
+type DashboardChange struct {
+	// Path is the JSON path of the value changed, like $.panels[0].title
+	Path   string              `json:"path"`
+	Type   DashboardChangeType `json:"type"`
+	Before interface{}         `json:"before,omitempty"`
+	After  interface{}         `json:"after,omitempty"`
+}
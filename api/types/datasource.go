@@ -18,9 +18,11 @@ type DataSource struct {
 	IsDefault   bool        `json:"isDefault"`
 	ReadOnly    bool        `json:"readOnly"`
 	ID          int64       `json:"id"`
+	UID         string      `json:"uid"`
 	OrgID       int64       `json:"orgId"`
 	Name        string      `json:"name"`
 	Type        string      `json:"type"`
+	TypeName    string      `json:"typeName"`
 	TypeLogoURL string      `json:"typeLogoUrl"`
 	Access      string      `json:"access"`
 	URL         string      `json:"url"`
@@ -29,12 +29,113 @@ type Folder struct {
 	UpdatedBy string    `json:"updatedBy"`
 	Updated   time.Time `json:"updated"`
 	Version   int       `json:"version"`
+	// ParentUID is the uid of the parent folder when the nested folders are enabled (Grafana >= 10).
+	// It's empty for a folder at the root.
+	ParentUID string `json:"parentUid,omitempty"`
+	// Parents are the ancestors of the folder, from the root to the direct parent
+	Parents []*Folder `json:"parents,omitempty"`
 }
 
 type SimpleFolder struct {
-	ID    int64  `json:"id"`
-	UID   string `json:"uid"`
-	Title string `json:"title"`
+	ID        int64  `json:"id"`
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	ParentUID string `json:"parentUid,omitempty"`
+}
+
+type CreateFolder struct {
+	// UID is generated by Grafana when it's empty
+	UID         string `json:"uid,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// ParentUID is the folder in which the new folder is created. It requires the nested folders (Grafana >= 10).
+	ParentUID string `json:"parentUid,omitempty"`
+}
+
+type MoveFolder struct {
+	// ParentUID is the new parent of the folder. The folder is moved at the root when it's empty.
+	ParentUID string `json:"parentUid"`
+}
+
+// FolderDescendantCounts is the number of resources stored in a folder and in its subfolders
+type FolderDescendantCounts struct {
+	Folders       int64 `json:"folder"`
+	Dashboards    int64 `json:"dashboard"`
+	LibraryPanels int64 `json:"librarypanel"`
+	AlertRules    int64 `json:"alertrule"`
+}
+
+// FolderNode is a folder with its subfolders
+type FolderNode struct {
+	Folder   *SimpleFolder `json:"folder"`
+	Parent   *FolderNode   `json:"-"`
+	Children []*FolderNode `json:"children,omitempty"`
+}
+
+// Path returns the folders from the root to this one, i.e. its breadcrumbs
+func (n *FolderNode) Path() []*SimpleFolder {
+	var path []*SimpleFolder
+	for node := n; node != nil; node = node.Parent {
+		path = append([]*SimpleFolder{node.Folder}, path...)
+	}
+	return path
+}
+
+// FolderTree is the hierarchy of the folders of an organisation
+type FolderTree struct {
+	Roots []*FolderNode `json:"roots"`
+	nodes map[string]*FolderNode
+}
+
+// NewFolderTree builds the tree from a flat list of folders.
+// A folder whose parent isn't in the list is considered as a root.
+func NewFolderTree(folders []*SimpleFolder) *FolderTree {
+	tree := &FolderTree{nodes: make(map[string]*FolderNode, len(folders))}
+	for _, folder := range folders {
+		tree.nodes[folder.UID] = &FolderNode{Folder: folder}
+	}
+	for _, folder := range folders {
+		node := tree.nodes[folder.UID]
+		parent, exist := tree.nodes[folder.ParentUID]
+		if len(folder.ParentUID) == 0 || !exist {
+			tree.Roots = append(tree.Roots, node)
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	return tree
+}
+
+// Find returns the node of the folder or nil if the folder isn't in the tree
+func (t *FolderTree) Find(uid string) *FolderNode {
+	return t.nodes[uid]
+}
+
+// Walk calls fn for every folder, a parent before its children. The depth of a root is 0.
+func (t *FolderTree) Walk(fn func(node *FolderNode, depth int)) {
+	var walk func(nodes []*FolderNode, depth int)
+	walk = func(nodes []*FolderNode, depth int) {
+		for _, node := range nodes {
+			fn(node, depth)
+			walk(node.Children, depth+1)
+		}
+	}
+	walk(t.Roots, 0)
+}
+
+// Descendants returns every subfolder of the folder, at any depth
+func (t *FolderTree) Descendants(uid string) []*SimpleFolder {
+	node := t.Find(uid)
+	if node == nil {
+		return nil
+	}
+	var result []*SimpleFolder
+	subtree := &FolderTree{Roots: node.Children}
+	subtree.Walk(func(n *FolderNode, depth int) {
+		result = append(result, n.Folder)
+	})
+	return result
 }
 
 type UpdateFolder struct {
@@ -0,0 +1,107 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "time"
+
+type LibraryElementKind int
+
+const (
+	LibraryPanelKind    LibraryElementKind = 1
+	LibraryVariableKind LibraryElementKind = 2
+)
+
+type LibraryElementSortDirection string
+
+const (
+	LibraryElementSortAlphaAsc  LibraryElementSortDirection = "alpha-asc"
+	LibraryElementSortAlphaDesc LibraryElementSortDirection = "alpha-desc"
+)
+
+type LibraryElementUser struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatarUrl"`
+}
+
+type LibraryElementMeta struct {
+	FolderName          string             `json:"folderName"`
+	FolderUID           string             `json:"folderUid"`
+	ConnectedDashboards int64              `json:"connectedDashboards"`
+	Created             time.Time          `json:"created"`
+	Updated             time.Time          `json:"updated"`
+	CreatedBy           LibraryElementUser `json:"createdBy"`
+	UpdatedBy           LibraryElementUser `json:"updatedBy"`
+}
+
+type LibraryElement struct {
+	ID        int64              `json:"id"`
+	OrgID     int64              `json:"orgId"`
+	FolderID  int64              `json:"folderId"`
+	FolderUID string             `json:"folderUid"`
+	UID       string             `json:"uid"`
+	Name      string             `json:"name"`
+	Kind      LibraryElementKind `json:"kind"`
+	// Type is the type of the panel (or of the variable)
+	Type        string                 `json:"type"`
+	Description string                 `json:"description"`
+	Model       map[string]interface{} `json:"model"`
+	Version     int64                  `json:"version"`
+	Meta        LibraryElementMeta     `json:"meta"`
+}
+
+type LibraryElementSearchResult struct {
+	TotalCount int64             `json:"totalCount"`
+	Elements   []*LibraryElement `json:"elements"`
+	Page       int               `json:"page"`
+	PerPage    int               `json:"perPage"`
+}
+
+type CreateLibraryElement struct {
+	FolderID  int64  `json:"folderId"`
+	FolderUID string `json:"folderUid,omitempty"`
+	// UID is generated by Grafana when it's empty
+	UID   string                 `json:"uid,omitempty"`
+	Name  string                 `json:"name" binding:"Required"`
+	Model map[string]interface{} `json:"model" binding:"Required"`
+	Kind  LibraryElementKind     `json:"kind" binding:"Required"`
+}
+
+type PatchLibraryElement struct {
+	// FolderID is a pointer to be able to move the element into the General folder (id 0)
+	FolderID  *int64                 `json:"folderId,omitempty"`
+	FolderUID *string                `json:"folderUid,omitempty"`
+	UID       string                 `json:"uid,omitempty"`
+	Name      string                 `json:"name,omitempty"`
+	Model     map[string]interface{} `json:"model,omitempty"`
+	Kind      LibraryElementKind     `json:"kind" binding:"Required"`
+	// Version must be the current version of the element, otherwise Grafana rejects the modification
+	Version int64 `json:"version" binding:"Required"`
+}
+
+type LibraryElementConnectionKind int
+
+const (
+	LibraryElementDashboardConnection LibraryElementConnectionKind = 1
+)
+
+type LibraryElementConnection struct {
+	ID            int64                        `json:"id"`
+	Kind          LibraryElementConnectionKind `json:"kind"`
+	ElementID     int64                        `json:"elementId"`
+	ConnectionID  int64                        `json:"connectionId"`
+	ConnectionUID string                       `json:"connectionUid"`
+	Created       time.Time                    `json:"created"`
+	CreatedBy     LibraryElementUser           `json:"createdBy"`
+}
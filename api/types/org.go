@@ -96,7 +96,8 @@ type AddInvite struct {
 }
 
 type OrgPrefs struct {
-	Theme           string `json:"theme"`
-	HomeDashboardID int64  `json:"homeDashboardId"`
-	Timezone        string `json:"timezone"`
+	Theme            string `json:"theme"`
+	HomeDashboardID  int64  `json:"homeDashboardId"`
+	HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+	Timezone         string `json:"timezone"`
 }
@@ -0,0 +1,45 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+type PreferenceLevel string
+
+const (
+	PreferenceLevelDefault PreferenceLevel = "default"
+	PreferenceLevelOrg     PreferenceLevel = "org"
+	PreferenceLevelTeam    PreferenceLevel = "team"
+	PreferenceLevelUser    PreferenceLevel = "user"
+)
+
+// PatchPreferences is used to update only some preferences of a user, a team or an organisation.
+// A nil field is left unchanged. Supported since Grafana 8.5
+type PatchPreferences struct {
+	Theme *string `json:"theme,omitempty"`
+	// HomeDashboardUID set to an empty string clears the home dashboard
+	HomeDashboardUID *string `json:"homeDashboardUID,omitempty"`
+	Timezone         *string `json:"timezone,omitempty"`
+	WeekStart        *string `json:"weekStart,omitempty"`
+}
+
+// EffectiveHomeDashboard describes which home dashboard is used by a user and which level of preferences sets it
+type EffectiveHomeDashboard struct {
+	Level PreferenceLevel
+	// TeamID is the team whose preferences set the home dashboard when Level is PreferenceLevelTeam
+	TeamID           int64
+	HomeDashboardID  int64
+	HomeDashboardUID string
+	// UserLevelSkipped is true when the preferences of the user couldn't be read,
+	// because Grafana only exposes them to the user itself.
+	UserLevelSkipped bool
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "time"
+
+type PublicDashboardShareType string
+
+const (
+	PublicShareType PublicDashboardShareType = "public"
+	EmailShareType  PublicDashboardShareType = "email"
+)
+
+type PublicDashboard struct {
+	UID          string `json:"uid"`
+	DashboardUID string `json:"dashboardUid"`
+	// AccessToken is the token used in the public URL of the dashboard (/public-dashboards/:accessToken)
+	AccessToken          string                   `json:"accessToken"`
+	IsEnabled            bool                     `json:"isEnabled"`
+	TimeSelectionEnabled bool                     `json:"timeSelectionEnabled"`
+	AnnotationsEnabled   bool                     `json:"annotationsEnabled"`
+	Share                PublicDashboardShareType `json:"share"`
+	CreatedBy            int64                    `json:"createdBy"`
+	UpdatedBy            int64                    `json:"updatedBy"`
+	CreatedAt            time.Time                `json:"createdAt"`
+	UpdatedAt            time.Time                `json:"updatedAt"`
+}
+
+// SavePublicDashboard is used to create or update the public configuration of a dashboard.
+// The fields are pointers so an update only changes the fields that are set.
+type SavePublicDashboard struct {
+	// UID and AccessToken are generated by Grafana when they are not set during the creation
+	UID                  string                   `json:"uid,omitempty"`
+	AccessToken          string                   `json:"accessToken,omitempty"`
+	IsEnabled            *bool                    `json:"isEnabled,omitempty"`
+	TimeSelectionEnabled *bool                    `json:"timeSelectionEnabled,omitempty"`
+	AnnotationsEnabled   *bool                    `json:"annotationsEnabled,omitempty"`
+	Share                PublicDashboardShareType `json:"share,omitempty"`
+}
+
+type PublicDashboardListItem struct {
+	UID          string                   `json:"uid"`
+	AccessToken  string                   `json:"accessToken"`
+	Title        string                   `json:"title"`
+	DashboardUID string                   `json:"dashboardUid"`
+	Slug         string                   `json:"slug"`
+	IsEnabled    bool                     `json:"isEnabled"`
+	Share        PublicDashboardShareType `json:"share"`
+}
+
+type PublicDashboardList struct {
+	PublicDashboards []*PublicDashboardListItem `json:"publicDashboards"`
+	TotalCount       int64                      `json:"totalCount"`
+	Page             int                        `json:"page"`
+	PerPage          int                        `json:"perPage"`
+}
+
+// OrgPublicDashboard is a public dashboard found during an audit of all organisations
+type OrgPublicDashboard struct {
+	OrgID   int64  `json:"orgId"`
+	OrgName string `json:"orgName"`
+	PublicDashboardListItem
+}
@@ -20,6 +20,17 @@ const (
 	SearchDFolderType   SearchType = "dash-folder"
 )
 
+// SearchSort is the order of the results of a search
+type SearchSort string
+
+const (
+	SearchSortAlphaAsc  SearchSort = "alpha-asc"
+	SearchSortAlphaDesc SearchSort = "alpha-desc"
+)
+
+// GeneralFolderUID is the uid to use to search the dashboards of the General folder
+const GeneralFolderUID = "general"
+
 type SearchResult struct {
 	ID          int64      `json:"id"`
 	UID         string     `json:"uid"`
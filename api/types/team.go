@@ -42,3 +42,10 @@ type CreateOrUpdateTeam struct {
 	Name  string `json:"name" binding:"Required"`
 	Email string `json:"email"`
 }
+
+type TeamPrefs struct {
+	Theme            string `json:"theme"`
+	HomeDashboardID  int64  `json:"homeDashboardId"`
+	HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+	Timezone         string `json:"timezone"`
+}
@@ -64,9 +64,10 @@ type UpdateUserQuota struct {
 }
 
 type UserPreference struct {
-	Theme           string `json:"theme"`
-	HomeDashboardID int64  `json:"homeDashboardId"`
-	Timezone        string `json:"timezone"`
+	Theme            string `json:"theme"`
+	HomeDashboardID  int64  `json:"homeDashboardId"`
+	HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+	Timezone         string `json:"timezone"`
 }
 
 type UserOrg struct {
@@ -80,6 +81,12 @@ type UserOrgList struct {
 	Result []*UserOrg
 }
 
+// StarsSync lists the changes done to synchronize the starred dashboards of a user
+type StarsSync struct {
+	Starred   []string `json:"starred,omitempty"`
+	Unstarred []string `json:"unstarred,omitempty"`
+}
+
 type UpdatePassword struct {
 	OldPassword string `json:"oldPassword"`
 	NewPassword string `json:"newPassword"`
@@ -14,6 +14,7 @@
 package api
 
 import (
+	"sort"
 	"strconv"
 
 	"github.com/nexucis/grafana-go-client/api/types"
@@ -29,12 +30,31 @@ type CurrentUserInterface interface {
 	GetOrg() (*types.UserOrgList, error)
 	StarDashboard(int64) error
 	UnstarDashboard(int64) error
+	// GetStarredDashboards returns the dashboards starred by the current user in the current organisation
+	GetStarredDashboards() ([]*types.SearchResult, error)
+	// StarDashboardByUID stars the dashboard for the current user (Grafana >= 9.1)
+	StarDashboardByUID(uid string) error
+	// UnstarDashboardByUID removes the star of the dashboard for the current user (Grafana >= 9.1)
+	UnstarDashboardByUID(uid string) error
+	// SyncStarredDashboards stars the dashboards of the list and unstars the other ones,
+	// so the starred dashboards of the current user are exactly the ones given.
+	// To set the starred dashboards of another user, the client must be authenticated as this user.
+	SyncStarredDashboards(uids []string) (*types.StarsSync, error)
 	UpdatePassword(string, string) error
 	GetQuotas() (*types.UserQuota, error)
 	AddHelpFlags(int64) error
 	ClearHelpFlags() error
 	GetPreference() (*types.UserPreference, error)
 	UpdatePreference(*types.UserPreference) error
+	PatchPreference(*types.PatchPreferences) error
+	// SetHomeDashboard sets the home dashboard of the current user. An empty uid clears it.
+	SetHomeDashboard(uid string) error
+	// GetOwnEffectiveHomeDashboard returns the home dashboard used by the current user
+	// and tells if it comes from the preferences of the user, of one of the user's teams or of the organisation.
+	// See UsersInterface.GetEffectiveHomeDashboard for any other user.
+	GetOwnEffectiveHomeDashboard() (*types.EffectiveHomeDashboard, error)
+	// GetTeams returns the teams of the current user in the current organisation
+	GetTeams() ([]*types.Team, error)
 }
 
 func newCurrentUser(client *grafanahttp.RESTClient) CurrentUserInterface {
@@ -88,6 +108,60 @@ func (c *currentUser) StarDashboard(dashboardID int64) error {
 		Error()
 }
 
+func (c *currentUser) GetStarredDashboards() ([]*types.SearchResult, error) {
+	return newSearch(c.client).QueryAll(QueryParameterSearch{SearchType: types.SearchDashboardType, Starred: true})
+}
+
+func (c *currentUser) StarDashboardByUID(uid string) error {
+	return c.client.Post(currentUserAPI).
+		SetSubPath("/stars/dashboard/uid/:uid").
+		SetPathParam("uid", uid).
+		Do().
+		Error()
+}
+
+func (c *currentUser) UnstarDashboardByUID(uid string) error {
+	return c.client.Delete(currentUserAPI).
+		SetSubPath("/stars/dashboard/uid/:uid").
+		SetPathParam("uid", uid).
+		Do().
+		Error()
+}
+
+func (c *currentUser) SyncStarredDashboards(uids []string) (*types.StarsSync, error) {
+	starred, err := c.GetStarredDashboards()
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		wanted[uid] = true
+	}
+	current := make(map[string]bool, len(starred))
+	result := &types.StarsSync{}
+	for _, hit := range starred {
+		current[hit.UID] = true
+		if !wanted[hit.UID] {
+			if err := c.UnstarDashboardByUID(hit.UID); err != nil {
+				return result, err
+			}
+			result.Unstarred = append(result.Unstarred, hit.UID)
+		}
+	}
+	for _, uid := range uids {
+		if current[uid] {
+			continue
+		}
+		// a uid listed twice must be starred only once
+		current[uid] = true
+		if err := c.StarDashboardByUID(uid); err != nil {
+			return result, err
+		}
+		result.Starred = append(result.Starred, uid)
+	}
+	return result, nil
+}
+
 func (c *currentUser) UnstarDashboard(dashboardID int64) error {
 	return c.client.Delete(currentUserAPI).
 		SetSubPath("/stars/dashboard/:dashboardID").
@@ -145,3 +219,85 @@ func (c *currentUser) UpdatePreference(preference *types.UserPreference) error {
 		Do().
 		Error()
 }
+
+func (c *currentUser) PatchPreference(preference *types.PatchPreferences) error {
+	return c.client.Patch(currentUserAPI).
+		SetSubPath("/preferences").
+		Body(preference).
+		Do().
+		Error()
+}
+
+func (c *currentUser) SetHomeDashboard(uid string) error {
+	return c.PatchPreference(&types.PatchPreferences{HomeDashboardUID: &uid})
+}
+
+func (c *currentUser) GetOwnEffectiveHomeDashboard() (*types.EffectiveHomeDashboard, error) {
+	userPref, err := c.GetPreference()
+	if err != nil {
+		return nil, err
+	}
+	teams, err := c.GetTeams()
+	if err != nil {
+		return nil, err
+	}
+	return effectiveHomeDashboard(c.client, userPref, teams)
+}
+
+// effectiveHomeDashboard resolves the home dashboard like Grafana does: the preferences of the user first,
+// then the ones of the teams and finally the ones of the organisation.
+// When userPref is nil, the user level is skipped.
+func effectiveHomeDashboard(client *grafanahttp.RESTClient, userPref *types.UserPreference, teams []*types.Team) (*types.EffectiveHomeDashboard, error) {
+	if userPref != nil && (userPref.HomeDashboardID != 0 || len(userPref.HomeDashboardUID) > 0) {
+		return &types.EffectiveHomeDashboard{
+			Level:            types.PreferenceLevelUser,
+			HomeDashboardID:  userPref.HomeDashboardID,
+			HomeDashboardUID: userPref.HomeDashboardUID,
+		}, nil
+	}
+	userLevelSkipped := userPref == nil
+
+	// like Grafana, when several teams set a home dashboard, the one with the highest id wins
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].ID > teams[j].ID
+	})
+	teamClient := newTeam(client)
+	for _, team := range teams {
+		teamPref, err := teamClient.GetPreferences(team.ID)
+		if err != nil {
+			return nil, err
+		}
+		if teamPref.HomeDashboardID != 0 || len(teamPref.HomeDashboardUID) > 0 {
+			return &types.EffectiveHomeDashboard{
+				Level:            types.PreferenceLevelTeam,
+				TeamID:           team.ID,
+				HomeDashboardID:  teamPref.HomeDashboardID,
+				HomeDashboardUID: teamPref.HomeDashboardUID,
+				UserLevelSkipped: userLevelSkipped,
+			}, nil
+		}
+	}
+
+	orgPref, err := newCurrentOrg(client).GetPreferences()
+	if err != nil {
+		return nil, err
+	}
+	if orgPref.HomeDashboardID != 0 || len(orgPref.HomeDashboardUID) > 0 {
+		return &types.EffectiveHomeDashboard{
+			Level:            types.PreferenceLevelOrg,
+			HomeDashboardID:  orgPref.HomeDashboardID,
+			HomeDashboardUID: orgPref.HomeDashboardUID,
+			UserLevelSkipped: userLevelSkipped,
+		}, nil
+	}
+	return &types.EffectiveHomeDashboard{Level: types.PreferenceLevelDefault, UserLevelSkipped: userLevelSkipped}, nil
+}
+
+func (c *currentUser) GetTeams() ([]*types.Team, error) {
+	var result []*types.Team
+	err := c.client.Get(currentUserAPI).
+		SetSubPath("/teams").
+		Do().
+		SaveAsObj(&result)
+	return result, err
+}
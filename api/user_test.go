@@ -0,0 +1,75 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentUser_SyncStarredDashboards(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == searchAPI {
+			assert.Equal(t, "true", r.URL.Query().Get("starred"))
+			_ = json.NewEncoder(w).Encode([]*types.SearchResult{{UID: "a"}, {UID: "b"}})
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		_, _ = w.Write([]byte(`{"message": "ok"}`))
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	result, err := newCurrentUser(rest).SyncStarredDashboards([]string{"b", "c", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.StarsSync{Starred: []string{"c"}, Unstarred: []string{"a"}}, result)
+	assert.Equal(t, []string{
+		"DELETE /api/user/stars/dashboard/uid/a",
+		"POST /api/user/stars/dashboard/uid/c",
+	}, requests)
+}
+
+func TestCurrentUser_GetOwnEffectiveHomeDashboard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/user/preferences":
+			_, _ = w.Write([]byte(`{"homeDashboardId": 0}`))
+		case "/api/user/teams":
+			_, _ = w.Write([]byte(`[{"id": 2, "name": "a"}, {"id": 5, "name": "b"}]`))
+		case "/api/teams/5/preferences":
+			_, _ = w.Write([]byte(`{"homeDashboardUID": "team-b-home"}`))
+		case "/api/teams/2/preferences":
+			_, _ = w.Write([]byte(`{"homeDashboardUID": "team-a-home"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	result, err := newCurrentUser(rest).GetOwnEffectiveHomeDashboard()
+	assert.Nil(t, err)
+	// like Grafana, the team with the highest id wins
+	assert.Equal(t, &types.EffectiveHomeDashboard{Level: types.PreferenceLevelTeam, TeamID: 5, HomeDashboardUID: "team-b-home"}, result)
+}
@@ -30,6 +30,13 @@ type UsersInterface interface {
 	GetOrgs(int64) (*types.UserOrgList, error)
 	Update(int64, *types.UpdateCurrentUser) error
 	UpdateUserActiveOrg(int64, int64) error
+	// GetTeams returns the teams of the user in the current organisation
+	GetTeams(userID int64) ([]*types.Team, error)
+	// GetEffectiveHomeDashboard returns the home dashboard used by the user in the current organisation
+	// and tells if it comes from the preferences of the user, of one of the user's teams or of the organisation.
+	// Grafana exposes the preferences of a user only to the user itself. When the client isn't authenticated as this user,
+	// the user level is skipped: the result only reflects the teams and the organisation and UserLevelSkipped is true.
+	GetEffectiveHomeDashboard(userID int64) (*types.EffectiveHomeDashboard, error)
 }
 
 func newUsers(client *grafanahttp.RESTClient) UsersInterface {
@@ -112,3 +119,29 @@ func (c *users) UpdateUserActiveOrg(userID int64, orgID int64) error {
 		Do().
 		Error()
 }
+
+func (c *users) GetTeams(userID int64) ([]*types.Team, error) {
+	var response []*types.Team
+	err := c.client.Get(usersAPI).
+		SetSubPath("/:id/teams").
+		SetPathParam("id", strconv.FormatInt(userID, 10)).
+		Do().
+		SaveAsObj(&response)
+	return response, err
+}
+
+func (c *users) GetEffectiveHomeDashboard(userID int64) (*types.EffectiveHomeDashboard, error) {
+	currentUserClient := newCurrentUser(c.client)
+	profile, err := currentUserClient.Get()
+	if err != nil {
+		return nil, err
+	}
+	if profile.ID == userID {
+		return currentUserClient.GetOwnEffectiveHomeDashboard()
+	}
+	teams, err := c.GetTeams(userID)
+	if err != nil {
+		return nil, err
+	}
+	return effectiveHomeDashboard(c.client, nil, teams)
+}
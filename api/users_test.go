@@ -0,0 +1,83 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsers_GetEffectiveHomeDashboard(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		userID   int64
+		teams    string
+		expected *types.EffectiveHomeDashboard
+	}{
+		{
+			title:    "current user, the user level wins",
+			userID:   1,
+			expected: &types.EffectiveHomeDashboard{Level: types.PreferenceLevelUser, HomeDashboardUID: "user-home"},
+		},
+		{
+			title:    "other user, the team level wins",
+			userID:   2,
+			teams:    `[{"id": 3, "name": "a"}, {"id": 7, "name": "b"}]`,
+			expected: &types.EffectiveHomeDashboard{Level: types.PreferenceLevelTeam, TeamID: 7, HomeDashboardUID: "team-b-home", UserLevelSkipped: true},
+		},
+		{
+			title:    "other user without team, the org level wins",
+			userID:   2,
+			teams:    `[]`,
+			expected: &types.EffectiveHomeDashboard{Level: types.PreferenceLevelOrg, HomeDashboardUID: "org-home", UserLevelSkipped: true},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/user":
+					_, _ = w.Write([]byte(`{"id": 1, "login": "admin"}`))
+				case "/api/user/preferences":
+					_, _ = w.Write([]byte(`{"homeDashboardUID": "user-home"}`))
+				case "/api/user/teams":
+					_, _ = w.Write([]byte(`[]`))
+				case "/api/users/2/teams":
+					_, _ = w.Write([]byte(test.teams))
+				case "/api/teams/3/preferences":
+					_, _ = w.Write([]byte(`{}`))
+				case "/api/teams/7/preferences":
+					_, _ = w.Write([]byte(`{"homeDashboardUID": "team-b-home"}`))
+				case "/api/org/preferences":
+					_, _ = w.Write([]byte(`{"homeDashboardUID": "org-home"}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			rest, err := grafanahttp.NewWithURL(server.URL)
+			assert.Nil(t, err)
+			result, err := newUsers(rest).GetEffectiveHomeDashboard(test.userID)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
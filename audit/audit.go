@@ -0,0 +1,195 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit produces the list of who can access every folder and every dashboard of an organisation.
+// The permissions given to a team are expanded into its members and the ones given to a role into the users
+// having this role, so the report is a flat list of users that can be exported in CSV, JSON or Markdown.
+package audit
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/permission"
+)
+
+// ResourceKind is the kind of resource audited
+type ResourceKind string
+
+const (
+	FolderResource    ResourceKind = "folder"
+	DashboardResource ResourceKind = "dashboard"
+)
+
+// Resource is a folder or a dashboard with its ACL
+type Resource struct {
+	Kind ResourceKind
+	UID  string
+	// Path is the titles of the parent folders and of the resource, separated by a slash
+	Path string
+	// ACL contains the entries set on the resource and the ones inherited from its folder
+	ACL []*types.FolderOrDashboardPermission
+}
+
+// Row is a permission of a user on a resource
+type Row struct {
+	User         string       `json:"user"`
+	ResourceKind ResourceKind `json:"resourceKind"`
+	ResourceUID  string       `json:"resourceUid"`
+	ResourcePath string       `json:"resourcePath"`
+	// Access is how the user gets the permission: user, team <name>, role <role> or org role Admin
+	Access     string `json:"access"`
+	Permission string `json:"permission"`
+	Inherited  bool   `json:"inherited"`
+}
+
+type Report struct {
+	Rows []*Row `json:"rows"`
+}
+
+type Options struct {
+	// SkipOrgAdmins removes the rows of the administrators of the organisation, who have the permission Admin everywhere
+	SkipOrgAdmins bool
+}
+
+// Build expands the ACL of every resource into the users of the directory.
+// A user appears once per entry giving them a permission, so they can appear several times for the same resource.
+func Build(directory *permission.Directory, resources []*Resource, options Options) *Report {
+	report := &Report{}
+	members := directory.SortedMembers()
+	for _, resource := range resources {
+		add := func(member *permission.Member, access string, permissionType types.PermissionType, inherited bool) {
+			report.Rows = append(report.Rows, &Row{
+				User:         member.Login,
+				ResourceKind: resource.Kind,
+				ResourceUID:  resource.UID,
+				ResourcePath: resource.Path,
+				Access:       access,
+				Permission:   permission.PermissionName(permissionType),
+				Inherited:    inherited,
+			})
+		}
+		for _, member := range members {
+			if member.OrgRole == types.RoleAdmin {
+				if !options.SkipOrgAdmins {
+					add(member, "org role Admin", types.PermissionAdmin, false)
+				}
+				continue
+			}
+			for _, entry := range resource.ACL {
+				access, applies := accessOf(directory, member, entry)
+				if applies {
+					add(member, access, entry.Permission, entry.Inherited)
+				}
+			}
+		}
+	}
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.ResourcePath != b.ResourcePath {
+			return a.ResourcePath < b.ResourcePath
+		}
+		if a.ResourceKind != b.ResourceKind {
+			return a.ResourceKind == FolderResource
+		}
+		return a.User < b.User
+	})
+	return report
+}
+
+// accessOf returns how the entry applies to the user, if it does
+func accessOf(directory *permission.Directory, member *permission.Member, entry *types.FolderOrDashboardPermission) (string, bool) {
+	if !permission.Applies(member, entry) {
+		return "", false
+	}
+	switch {
+	case entry.UserID > 0:
+		return "user", true
+	case entry.TeamID > 0:
+		name := entry.Team
+		if team, exist := directory.Teams[entry.TeamID]; exist && len(name) == 0 {
+			name = team.Name
+		}
+		return "team " + name, true
+	default:
+		return "role " + string(*entry.Role), true
+	}
+}
+
+// Run audits every folder and every dashboard of the current organisation
+func Run(client api.ClientInterface, options Options) (*Report, error) {
+	directory, err := permission.LoadDirectory(client)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := Resources(client)
+	if err != nil {
+		return nil, err
+	}
+	return Build(directory, resources, options), nil
+}
+
+// Resources gathers the ACL of every folder and every dashboard of the current organisation
+func Resources(client api.ClientInterface) ([]*Resource, error) {
+	tree, err := client.Folders().GetTree()
+	if err != nil {
+		return nil, err
+	}
+	var resources []*Resource
+	var walkErr error
+	tree.Walk(func(node *types.FolderNode, depth int) {
+		if walkErr != nil {
+			return
+		}
+		acl, err := client.Folders().GetPermissions(node.Folder.UID)
+		if err != nil {
+			walkErr = err
+			return
+		}
+		resources = append(resources, &Resource{Kind: FolderResource, UID: node.Folder.UID, Path: folderPath(tree, node.Folder.UID), ACL: acl})
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	hits, err := client.Search().QueryAll(api.QueryParameterSearch{SearchType: types.SearchDashboardType})
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range hits {
+		acl, err := client.Dashboards().GetPermissionsByUID(hit.UID)
+		if err != nil {
+			return nil, err
+		}
+		path := hit.Title
+		if len(hit.FolderUID) > 0 {
+			path = folderPath(tree, hit.FolderUID) + "/" + hit.Title
+		}
+		resources = append(resources, &Resource{Kind: DashboardResource, UID: hit.UID, Path: path, ACL: acl})
+	}
+	return resources, nil
+}
+
+func folderPath(tree *types.FolderTree, uid string) string {
+	node := tree.Find(uid)
+	if node == nil {
+		return uid
+	}
+	var titles []string
+	for _, folder := range node.Path() {
+		titles = append(titles, folder.Title)
+	}
+	return strings.Join(titles, "/")
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/permission"
+	"github.com/stretchr/testify/assert"
+)
+
+func rolePtr(role types.RoleType) *types.RoleType {
+	return &role
+}
+
+func newTestReport(options Options) *Report {
+	directory := &permission.Directory{
+		Members: map[int64]*permission.Member{
+			1: {ID: 1, Login: "admin", OrgRole: types.RoleAdmin},
+			2: {ID: 2, Login: "alice", OrgRole: types.RoleEditor, TeamIDs: []int64{3}},
+			3: {ID: 3, Login: "bob", OrgRole: types.RoleViewer},
+		},
+		Teams: map[int64]*types.Team{3: {ID: 3, Name: "Backend"}},
+	}
+	resources := []*Resource{
+		{Kind: DashboardResource, UID: "api", Path: "Ops/API", ACL: []*types.FolderOrDashboardPermission{
+			{Inherited: true, Role: rolePtr(types.RoleViewer), Permission: types.PermissionView},
+			{Inherited: true, TeamID: 3, Permission: types.PermissionEdit},
+			{UserID: 3, Permission: types.PermissionAdmin},
+		}},
+		{Kind: FolderResource, UID: "ops", Path: "Ops", ACL: []*types.FolderOrDashboardPermission{
+			{Role: rolePtr(types.RoleEditor), Permission: types.PermissionEdit},
+		}},
+	}
+	return Build(directory, resources, options)
+}
+
+func TestBuild(t *testing.T) {
+	report := newTestReport(Options{SkipOrgAdmins: true})
+	assert.Equal(t, []*Row{
+		{User: "alice", ResourceKind: FolderResource, ResourceUID: "ops", ResourcePath: "Ops", Access: "role Editor", Permission: "Edit"},
+		{User: "alice", ResourceKind: DashboardResource, ResourceUID: "api", ResourcePath: "Ops/API", Access: "role Viewer", Permission: "View", Inherited: true},
+		{User: "alice", ResourceKind: DashboardResource, ResourceUID: "api", ResourcePath: "Ops/API", Access: "team Backend", Permission: "Edit", Inherited: true},
+		{User: "bob", ResourceKind: DashboardResource, ResourceUID: "api", ResourcePath: "Ops/API", Access: "role Viewer", Permission: "View", Inherited: true},
+		{User: "bob", ResourceKind: DashboardResource, ResourceUID: "api", ResourcePath: "Ops/API", Access: "user", Permission: "Admin"},
+	}, report.Rows)
+
+	assert.Len(t, newTestReport(Options{}).Rows, 7)
+}
+
+func TestReport_Write(t *testing.T) {
+	report := &Report{Rows: []*Row{
+		{User: "alice", ResourceKind: FolderResource, ResourceUID: "ops", ResourcePath: "Ops|Prod", Access: "role Editor", Permission: "Edit"},
+	}}
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, report.WriteCSV(buffer))
+	assert.Equal(t, "user,resource kind,resource uid,resource path,access,permission,inherited\n"+
+		"alice,folder,ops,Ops|Prod,role Editor,Edit,false\n", buffer.String())
+
+	buffer.Reset()
+	assert.Nil(t, report.WriteMarkdown(buffer))
+	assert.Equal(t, "| user | resource kind | resource uid | resource path | access | permission | inherited |\n"+
+		"| --- | --- | --- | --- | --- | --- | --- |\n"+
+		"| alice | folder | ops | Ops\\|Prod | role Editor | Edit | false |\n", buffer.String())
+
+	buffer.Reset()
+	assert.Nil(t, report.WriteJSON(buffer))
+	assert.Contains(t, buffer.String(), `"resourcePath": "Ops|Prod"`)
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bulk applies the same modification to many dashboards. Every dashboard is saved with
+// DashboardInterface.Modify, so a concurrent modification is never overwritten, and the result of each
+// dashboard is reported separately so one failure doesn't stop the others.
+package bulk
+
+import (
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+// Selection chooses the dashboards to modify
+type Selection struct {
+	// UIDs are the dashboards to modify. When it's empty, the dashboards are selected with Query.
+	UIDs []string
+	// Query selects the dashboards with a search. The zero value selects every dashboard.
+	Query api.QueryParameterSearch
+}
+
+type Options struct {
+	// Message is the commit message used when a dashboard is saved
+	Message string
+	// DryRun allows to get the report without saving any dashboard
+	DryRun bool
+}
+
+// MutateFunc modifies the dashboard. It returns api.ErrNotModified when there is nothing to change.
+type MutateFunc func(model types.DashboardModel) error
+
+// DashboardResult is the result of the modification of one dashboard
+type DashboardResult struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+	// Changes are the differences between the dashboard before and after the modification
+	Changes []*types.DashboardChange `json:"changes,omitempty"`
+	// Version is the version of the dashboard once saved. It's 0 when it's a dry run.
+	Version int `json:"version,omitempty"`
+	// Error is set when the dashboard couldn't be modified or saved
+	Error string `json:"error,omitempty"`
+}
+
+type Report struct {
+	// Dashboards contains only the dashboards modified (or that would be modified in dry run) and the ones that failed
+	Dashboards []*DashboardResult `json:"dashboards"`
+}
+
+// Failed returns the dashboards that couldn't be modified
+func (r *Report) Failed() []*DashboardResult {
+	var result []*DashboardResult
+	for _, dashboardResult := range r.Dashboards {
+		if len(dashboardResult.Error) > 0 {
+			result = append(result, dashboardResult)
+		}
+	}
+	return result
+}
+
+// Run applies the modification to every dashboard selected.
+// An error is returned only if the selection fails, the errors related to a dashboard are set in its result.
+func Run(client api.ClientInterface, selection Selection, options Options, mutate MutateFunc) (*Report, error) {
+	uids, err := selectDashboards(client, selection)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for _, uid := range uids {
+		result := &DashboardResult{UID: uid}
+		saved, err := client.Dashboards().Modify(uid, options.Message, func(model types.DashboardModel) error {
+			result.Title = model.Title()
+			before, err := dashboard.Copy(model)
+			if err != nil {
+				return err
+			}
+			if err := mutate(model); err != nil {
+				return err
+			}
+			if result.Changes, err = dashboard.Diff(before, model); err != nil {
+				return err
+			}
+			if len(result.Changes) == 0 || options.DryRun {
+				return api.ErrNotModified
+			}
+			return nil
+		})
+		if err != nil {
+			result.Error = err.Error()
+		} else if saved != nil {
+			result.Version = saved.Version
+		}
+		if len(result.Changes) > 0 || len(result.Error) > 0 {
+			report.Dashboards = append(report.Dashboards, result)
+		}
+	}
+	return report, nil
+}
+
+func selectDashboards(client api.ClientInterface, selection Selection) ([]string, error) {
+	if len(selection.UIDs) > 0 {
+		return selection.UIDs, nil
+	}
+	query := selection.Query
+	query.SearchType = types.SearchDashboardType
+	hits, err := client.Search().QueryAll(query)
+	if err != nil {
+		return nil, err
+	}
+	uids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		uids = append(uids, hit.UID)
+	}
+	return uids, nil
+}
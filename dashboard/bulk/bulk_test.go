@@ -0,0 +1,252 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+// bulkTestDashboards are the dashboards of the fake Grafana, by uid
+var bulkTestDashboards = map[string]string{
+	"node": `{"uid": "node", "title": "Node", "version": 3, "tags": ["prod", "linux"],
+		"panels": [{"id": 1, "type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom-old"},
+			"targets": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "prom-old"}}]}]}`,
+	"api": `{"uid": "api", "title": "API", "version": 7, "tags": ["prod"],
+		"panels": [{"id": 1, "type": "stat", "datasource": "Prometheus old"}]}`,
+	"logs": `{"uid": "logs", "title": "Logs", "version": 1, "tags": ["staging"],
+		"panels": [{"id": 1, "type": "logs", "datasource": {"type": "loki", "uid": "loki"}}]}`,
+}
+
+// fakeGrafana serves bulkTestDashboards. The search returns them sorted by uid, filtered by tag.
+type fakeGrafana struct {
+	// searches contains the query of every search
+	searches []string
+	// saved contains the dashboards saved
+	saved []*types.SaveDashboard
+	// failSave contains the uids of the dashboards that can't be saved
+	failSave map[string]bool
+}
+
+func newFakeGrafana(t *testing.T) (*fakeGrafana, api.ClientInterface, func()) {
+	fake := &fakeGrafana{failSave: make(map[string]bool)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/search":
+			fake.searches = append(fake.searches, r.URL.RawQuery)
+			tags := r.URL.Query()["tag"]
+			var hits []*types.SearchResult
+			for _, uid := range []string{"api", "logs", "node"} {
+				model := newTestDashboard(t, bulkTestDashboards[uid])
+				if len(tags) == 0 || hasTags(model, tags) {
+					hits = append(hits, &types.SearchResult{UID: uid, Title: model.Title(), Type: types.SearchDashboardType})
+				}
+			}
+			response = hits
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/dashboards/uid/"):
+			content, exist := bulkTestDashboards[strings.TrimPrefix(r.URL.Path, "/api/dashboards/uid/")]
+			if !exist {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message": "Dashboard not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"meta": {"folderUid": "folder"}, "dashboard": ` + content + `}`))
+			return
+		case r.Method == http.MethodPost && r.URL.Path == "/api/dashboards/db":
+			saved := &types.SaveDashboard{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(saved))
+			if fake.failSave[saved.Dashboard.UID()] {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"message": "failed to save dashboard"}`))
+				return
+			}
+			fake.saved = append(fake.saved, saved)
+			version, _ := saved.Dashboard["version"].(float64)
+			response = &types.SimpleDashboard{UID: saved.Dashboard.UID(), Version: int(version) + 1, Status: "success"}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	return fake, api.NewWithClient(rest), server.Close
+}
+
+func newTestDashboard(t *testing.T, content string) types.DashboardModel {
+	model := make(types.DashboardModel)
+	assert.Nil(t, json.Unmarshal([]byte(content), &model))
+	return model
+}
+
+func hasTags(model types.DashboardModel, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, modelTag := range dashboard.Tags(model) {
+			found = found || modelTag == tag
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// setTitle modifies every dashboard
+func setTitle(model types.DashboardModel) error {
+	model["title"] = model.Title() + " (migrated)"
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	report, err := Run(client, Selection{UIDs: []string{"node", "api"}}, Options{Message: "migrated"}, setTitle)
+	assert.Nil(t, err)
+	assert.Empty(t, fake.searches)
+	assert.Empty(t, report.Failed())
+	if assert.Len(t, report.Dashboards, 2) {
+		assert.Equal(t, "node", report.Dashboards[0].UID)
+		assert.Equal(t, "Node", report.Dashboards[0].Title)
+		assert.Equal(t, 4, report.Dashboards[0].Version)
+		assert.Len(t, report.Dashboards[0].Changes, 1)
+		assert.Equal(t, "api", report.Dashboards[1].UID)
+		assert.Equal(t, 8, report.Dashboards[1].Version)
+	}
+	if assert.Len(t, fake.saved, 2) {
+		assert.Equal(t, "migrated", fake.saved[0].Message)
+		assert.Equal(t, "Node (migrated)", fake.saved[0].Dashboard.Title())
+		assert.False(t, fake.saved[0].Overwrite)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	report, err := Run(client, Selection{UIDs: []string{"node", "api"}}, Options{DryRun: true}, setTitle)
+	assert.Nil(t, err)
+	assert.Empty(t, fake.saved)
+	if assert.Len(t, report.Dashboards, 2) {
+		for _, result := range report.Dashboards {
+			assert.NotEmpty(t, result.Changes)
+			assert.Zero(t, result.Version)
+			assert.Empty(t, result.Error)
+		}
+	}
+}
+
+func TestRun_NotModified(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	report, err := Run(client, Selection{UIDs: []string{"node", "api", "logs"}}, Options{}, func(model types.DashboardModel) error {
+		if model.UID() != "api" {
+			return api.ErrNotModified
+		}
+		return setTitle(model)
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, report.Dashboards, 1) {
+		assert.Equal(t, "api", report.Dashboards[0].UID)
+	}
+	assert.Len(t, fake.saved, 1)
+}
+
+func TestRun_DashboardErrors(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+	fake.failSave["node"] = true
+
+	report, err := Run(client, Selection{UIDs: []string{"node", "missing", "api"}}, Options{}, setTitle)
+	assert.Nil(t, err)
+	failed := report.Failed()
+	if assert.Len(t, failed, 2) {
+		assert.Equal(t, "node", failed[0].UID)
+		assert.Contains(t, failed[0].Error, "failed to save dashboard")
+		assert.Equal(t, "missing", failed[1].UID)
+		assert.NotEmpty(t, failed[1].Error)
+	}
+	// the dashboards after the failures are still modified
+	if assert.Len(t, fake.saved, 1) {
+		assert.Equal(t, "api", fake.saved[0].Dashboard.UID())
+	}
+	assert.Len(t, report.Dashboards, 3)
+}
+
+func TestRun_SearchSelection(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	selection := Selection{Query: api.QueryParameterSearch{Tags: []string{"prod"}, SearchType: types.SearchDFolderType}}
+	report, err := Run(client, selection, Options{DryRun: true}, setTitle)
+	assert.Nil(t, err)
+	if assert.Len(t, fake.searches, 1) {
+		// only the dashboards can be modified, whatever the type asked
+		assert.Contains(t, fake.searches[0], "type="+string(types.SearchDashboardType))
+		assert.NotContains(t, fake.searches[0], "type="+string(types.SearchDFolderType))
+		assert.Contains(t, fake.searches[0], "tag=prod")
+	}
+	var uids []string
+	for _, result := range report.Dashboards {
+		uids = append(uids, result.UID)
+	}
+	assert.Equal(t, []string{"api", "node"}, uids)
+}
+
+func TestReplaceDatasource(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	from := &types.DataSource{UID: "prom-old", Name: "Prometheus old", Type: "prometheus"}
+	to := dashboard.DatasourceTarget{Datasource: &types.DataSource{UID: "prom-new", Name: "Prometheus new", Type: "prometheus"}}
+	report, err := ReplaceDatasource(client, Selection{}, from, to, Options{Message: "new prometheus"})
+	assert.Nil(t, err)
+	assert.Empty(t, report.Failed())
+	// the logs dashboard doesn't use the datasource, it's neither saved nor reported
+	var uids []string
+	for _, result := range report.Dashboards {
+		uids = append(uids, result.UID)
+	}
+	assert.Equal(t, []string{"api", "node"}, uids)
+	if assert.Len(t, fake.saved, 2) {
+		assert.Equal(t, "new prometheus", fake.saved[0].Message)
+		assert.Equal(t, "Prometheus new", dashboard.Panels(fake.saved[0].Dashboard)[0]["datasource"])
+		panel := dashboard.Panels(fake.saved[1].Dashboard)[0]
+		assert.Equal(t, map[string]interface{}{"type": "prometheus", "uid": "prom-new"}, panel["datasource"])
+	}
+}
+
+func TestReplaceDatasource_InvalidTarget(t *testing.T) {
+	_, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	_, err := ReplaceDatasource(client, Selection{}, nil, dashboard.DatasourceTarget{Variable: "ds"}, Options{})
+	assert.NotNil(t, err)
+	_, err = ReplaceDatasource(client, Selection{}, &types.DataSource{UID: "prom-old"}, dashboard.DatasourceTarget{}, Options{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"fmt"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+// ReplaceDatasource rewrites, in every dashboard selected, the references to the datasource from
+// so they point to the new datasource or to a template variable (see dashboard.ReplaceDatasource).
+func ReplaceDatasource(client api.ClientInterface, selection Selection, from *types.DataSource, to dashboard.DatasourceTarget, options Options) (*Report, error) {
+	if from == nil {
+		return nil, fmt.Errorf("the datasource to replace is missing")
+	}
+	if err := to.Validate(); err != nil {
+		return nil, err
+	}
+	return Run(client, selection, options, func(model types.DashboardModel) error {
+		paths, err := dashboard.ReplaceDatasource(model, from, to)
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			return api.ErrNotModified
+		}
+		return nil
+	})
+}
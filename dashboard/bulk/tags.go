@@ -0,0 +1,65 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"fmt"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+// AddTags adds the tags to every dashboard selected that doesn't have them yet
+func AddTags(client api.ClientInterface, selection Selection, tags []string, options Options) (*Report, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tag to add")
+	}
+	return Run(client, selection, options, func(model types.DashboardModel) error {
+		if !dashboard.AddTags(model, tags...) {
+			return api.ErrNotModified
+		}
+		return nil
+	})
+}
+
+// RemoveTags removes the tags from every dashboard selected
+func RemoveTags(client api.ClientInterface, selection Selection, tags []string, options Options) (*Report, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tag to remove")
+	}
+	return Run(client, selection, options, func(model types.DashboardModel) error {
+		if !dashboard.RemoveTags(model, tags...) {
+			return api.ErrNotModified
+		}
+		return nil
+	})
+}
+
+// RenameTag renames the tag from into to in every dashboard selected (see dashboard.RenameTag).
+// When the selection has no uid and no tag filter, the search is restricted to the dashboards having the tag from.
+func RenameTag(client api.ClientInterface, selection Selection, from string, to string, options Options) (*Report, error) {
+	if len(from) == 0 || len(to) == 0 {
+		return nil, fmt.Errorf("the tag to rename and its new name are required")
+	}
+	if len(selection.UIDs) == 0 && len(selection.Query.Tags) == 0 {
+		selection.Query.Tags = []string{from}
+	}
+	return Run(client, selection, options, func(model types.DashboardModel) error {
+		if !dashboard.RenameTag(model, from, to) {
+			return api.ErrNotModified
+		}
+		return nil
+	})
+}
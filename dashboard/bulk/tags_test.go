@@ -0,0 +1,122 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func reportedUIDs(report *Report) []string {
+	var uids []string
+	for _, result := range report.Dashboards {
+		uids = append(uids, result.UID)
+	}
+	return uids
+}
+
+func TestAddTags(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	report, err := AddTags(client, Selection{UIDs: []string{"node", "api", "logs"}}, []string{"linux"}, Options{Message: "tag linux"})
+	assert.Nil(t, err)
+	// node already has the tag, it's neither saved nor reported
+	assert.Equal(t, []string{"api", "logs"}, reportedUIDs(report))
+	if assert.Len(t, fake.saved, 2) {
+		assert.Equal(t, "tag linux", fake.saved[0].Message)
+		assert.Equal(t, []string{"prod", "linux"}, dashboard.Tags(fake.saved[0].Dashboard))
+		assert.Equal(t, []string{"staging", "linux"}, dashboard.Tags(fake.saved[1].Dashboard))
+	}
+
+	_, err = AddTags(client, Selection{}, nil, Options{})
+	assert.NotNil(t, err)
+}
+
+func TestRemoveTags(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	report, err := RemoveTags(client, Selection{}, []string{"linux"}, Options{Message: "untag linux"})
+	assert.Nil(t, err)
+	// only node has the tag
+	assert.Equal(t, []string{"node"}, reportedUIDs(report))
+	if assert.Len(t, fake.saved, 1) {
+		assert.Equal(t, "untag linux", fake.saved[0].Message)
+		assert.Equal(t, []string{"prod"}, dashboard.Tags(fake.saved[0].Dashboard))
+	}
+
+	_, err = RemoveTags(client, Selection{}, nil, Options{})
+	assert.NotNil(t, err)
+}
+
+func TestRenameTag(t *testing.T) {
+	testSuites := []struct {
+		title     string
+		selection Selection
+		// searchedTags are the tags of the search, nil when there is no search
+		searchedTags []string
+		expected     []string
+	}{
+		{
+			title:        "search restricted to the tag renamed",
+			selection:    Selection{},
+			searchedTags: []string{"prod"},
+			expected:     []string{"api", "node"},
+		},
+		{
+			title:        "tag filter given",
+			selection:    Selection{Query: api.QueryParameterSearch{Tags: []string{"linux"}}},
+			searchedTags: []string{"linux"},
+			expected:     []string{"node"},
+		},
+		{
+			title:     "uids given",
+			selection: Selection{UIDs: []string{"logs", "api"}},
+			expected:  []string{"api"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			fake, client, closeServer := newFakeGrafana(t)
+			defer closeServer()
+
+			report, err := RenameTag(client, test.selection, "prod", "production", Options{Message: "rename prod"})
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, reportedUIDs(report))
+			if test.searchedTags == nil {
+				assert.Empty(t, fake.searches)
+			} else if assert.Len(t, fake.searches, 1) {
+				query, err := url.ParseQuery(fake.searches[0])
+				assert.Nil(t, err)
+				assert.Equal(t, test.searchedTags, query["tag"])
+			}
+			for _, saved := range fake.saved {
+				assert.Equal(t, "rename prod", saved.Message)
+				assert.Contains(t, dashboard.Tags(saved.Dashboard), "production")
+				assert.NotContains(t, dashboard.Tags(saved.Dashboard), "prod")
+			}
+			assert.Len(t, fake.saved, len(test.expected))
+		})
+	}
+
+	_, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+	_, err := RenameTag(client, Selection{}, "", "production", Options{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,245 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clone copies a dashboard into another folder, another organisation or another Grafana.
+package clone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+// ErrOverwriteSource is returned when the copy would overwrite the dashboard it's copied from
+var ErrOverwriteSource = errors.New("the copy would overwrite the source dashboard, change its uid, its title or its folder")
+
+type PermissionMode string
+
+const (
+	// KeepPermissions lets Grafana apply its default permissions to the copy
+	KeepPermissions PermissionMode = ""
+	// PreservePermissions copies the permissions set explicitly on the source dashboard
+	PreservePermissions PermissionMode = "preserve"
+	// ResetPermissions removes the permissions set explicitly on the copy, so it only inherits the ones of its folder
+	ResetPermissions PermissionMode = "reset"
+)
+
+// Target is where the copy is created
+type Target struct {
+	// Client is the Grafana where the copy is created. When it's nil, the Grafana of the source is used.
+	Client api.ClientInterface
+	// OrgID is the organisation of the copy. When it's 0, the current organisation of the user is used.
+	OrgID int64
+	// FolderUID is the folder of the copy. When it's empty, the copy is created in the General folder.
+	FolderUID string
+}
+
+type Options struct {
+	// UID of the copy. Grafana generates one when it's empty.
+	UID string
+	// Title of the copy. The title of the source is kept when it's empty.
+	Title string
+	// Message is the commit message of the copy
+	Message string
+	// Overwrite replaces the dashboard of the target having the same uid or the same title in the folder.
+	// The source dashboard itself is never overwritten, ErrOverwriteSource is returned instead.
+	Overwrite bool
+	// DatasourceMapping tells how the datasources of the source are matched with the ones of the target.
+	// When it's empty, the references to the datasources are kept as they are.
+	DatasourceMapping dashboard.DatasourceMapping
+	// InlineLibraryPanels replaces the library panels by their model, which is required when they don't exist in the target
+	InlineLibraryPanels bool
+	Permissions         PermissionMode
+}
+
+type Result struct {
+	Dashboard *types.SimpleDashboard `json:"dashboard"`
+	// Warnings describe what couldn't be copied as requested, like a datasource that doesn't exist in the target
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Dashboard copies the dashboard uid of the source into the target
+func Dashboard(source api.ClientInterface, uid string, target Target, options Options) (*Result, error) {
+	original, err := source.Dashboards().GetByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	model, err := dashboard.Copy(original.Dashboard)
+	if err != nil {
+		return nil, err
+	}
+	delete(model, "id")
+	delete(model, "version")
+	delete(model, "uid")
+	if len(options.UID) > 0 {
+		model["uid"] = options.UID
+	}
+	if len(options.Title) > 0 {
+		model["title"] = options.Title
+	}
+
+	sameGrafana := target.Client == nil
+	targetClient := target.Client
+	if sameGrafana {
+		targetClient = source
+	}
+	if target.OrgID > 0 {
+		targetClient = api.NewWithClient(targetClient.RESTClient().WithOrgID(target.OrgID))
+	}
+	// the library panels, the datasources and the teams belong to an organisation
+	sameOrg, err := isSameOrg(source, target, sameGrafana)
+	if err != nil {
+		return nil, err
+	}
+	if options.Overwrite && sameOrg && overwritesSource(original, model, target.FolderUID) {
+		return nil, ErrOverwriteSource
+	}
+
+	result := &Result{}
+	if err := copyLibraryPanels(source, model, options, sameOrg, result); err != nil {
+		return nil, err
+	}
+	if len(options.DatasourceMapping) > 0 {
+		if err := remapDatasources(source, targetClient, model, options.DatasourceMapping, result); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Dashboard, err = targetClient.Dashboards().Create(&types.SaveDashboard{
+		Dashboard: model,
+		FolderUID: target.FolderUID,
+		Message:   options.Message,
+		Overwrite: options.Overwrite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch options.Permissions {
+	case PreservePermissions:
+		err = preservePermissions(source, targetClient, original.Dashboard, result, sameGrafana, sameOrg)
+	case ResetPermissions:
+		err = targetClient.Dashboards().UpdatePermissionsByUID(result.Dashboard.UID, []*types.DashboardACLUpdateItem{})
+	}
+	if err != nil {
+		return result, fmt.Errorf("the dashboard has been copied but its permissions couldn't be set: %s", err)
+	}
+	return result, nil
+}
+
+// isSameOrg returns true if the target is the organisation of the source
+func isSameOrg(source api.ClientInterface, target Target, sameGrafana bool) (bool, error) {
+	if !sameGrafana {
+		return false, nil
+	}
+	if target.OrgID == 0 {
+		return true, nil
+	}
+	org, err := source.CurrentOrganisation().Get()
+	if err != nil {
+		return false, err
+	}
+	return org.ID == target.OrgID, nil
+}
+
+// overwritesSource returns true if saving the copy in the organisation of the source would replace the source,
+// because the copy has the same uid or the same title in the same folder
+func overwritesSource(original *types.DashboardWithMeta, model types.DashboardModel, folderUID string) bool {
+	if len(model.UID()) > 0 && model.UID() == original.Dashboard.UID() {
+		return true
+	}
+	return normalizeFolderUID(folderUID) == normalizeFolderUID(original.Meta.FolderUID) &&
+		strings.EqualFold(model.Title(), original.Dashboard.Title())
+}
+
+func normalizeFolderUID(uid string) string {
+	if uid == types.GeneralFolderUID {
+		return ""
+	}
+	return uid
+}
+
+func copyLibraryPanels(source api.ClientInterface, model types.DashboardModel, options Options, sameOrg bool, result *Result) error {
+	uids := dashboard.LibraryPanelUIDs(model)
+	if len(uids) == 0 {
+		return nil
+	}
+	if !options.InlineLibraryPanels {
+		if !sameOrg {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("the library panels %s must exist in the target", strings.Join(uids, ", ")))
+		}
+		return nil
+	}
+	elements := make(map[string]*types.LibraryElement, len(uids))
+	for _, uid := range uids {
+		element, err := source.LibraryElements().GetByUID(uid)
+		if err != nil {
+			return err
+		}
+		elements[uid] = element
+	}
+	_, err := dashboard.InlineLibraryPanels(model, elements)
+	return err
+}
+
+func remapDatasources(source api.ClientInterface, target api.ClientInterface, model types.DashboardModel, mapping dashboard.DatasourceMapping, result *Result) error {
+	sourceDatasources, err := source.DataSources().Get()
+	if err != nil {
+		return err
+	}
+	targetDatasources, err := target.DataSources().Get()
+	if err != nil {
+		return err
+	}
+	unresolved, err := dashboard.RemapDatasources(model, sourceDatasources, targetDatasources, mapping)
+	if err != nil {
+		return err
+	}
+	for _, key := range unresolved {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("the datasource '%s' has no equivalent in the target", key))
+	}
+	return nil
+}
+
+func preservePermissions(source api.ClientInterface, target api.ClientInterface, original types.DashboardModel, result *Result, sameGrafana bool, sameOrg bool) error {
+	if !sameGrafana {
+		result.Warnings = append(result.Warnings, "the permissions can't be preserved in another Grafana since the users and the teams are different")
+		return nil
+	}
+	permissions, err := source.Dashboards().GetPermissionsByUID(original.UID())
+	if err != nil {
+		return err
+	}
+	items := make([]*types.DashboardACLUpdateItem, 0, len(permissions))
+	for _, permission := range permissions {
+		if permission.Inherited {
+			continue
+		}
+		// the teams exist only in their organisation, unlike the users
+		if permission.TeamID > 0 && !sameOrg {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("the permission of the team '%s' can't be preserved in another organisation", permission.Team))
+			continue
+		}
+		items = append(items, &types.DashboardACLUpdateItem{
+			UserID:     permission.UserID,
+			TeamID:     permission.TeamID,
+			Role:       permission.Role,
+			Permission: permission.Permission,
+		})
+	}
+	return target.Dashboards().UpdatePermissionsByUID(result.Dashboard.UID, items)
+}
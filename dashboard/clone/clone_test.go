@@ -0,0 +1,228 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clone
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+const cloneTestDashboard = `{
+  "meta": {"folderUid": "team-a"},
+  "dashboard": {
+    "id": 12,
+    "uid": "source",
+    "title": "My dashboard",
+    "version": 4,
+    "panels": [{"id": 1, "type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom-main"}}]
+  }
+}`
+
+// fakeGrafana is a Grafana with two organisations. The organisation 1 is the current one of the user
+// and holds the source dashboard. Each organisation has its own Prometheus datasource.
+type fakeGrafana struct {
+	// saved contains the dashboards saved, by organisation
+	saved map[string][]*types.SaveDashboard
+	// permissions contains the permissions set on the copy, by organisation
+	permissions map[string][]*types.DashboardACLUpdateItem
+}
+
+func newFakeGrafana(t *testing.T) (*fakeGrafana, api.ClientInterface, func()) {
+	fake := &fakeGrafana{
+		saved:       make(map[string][]*types.SaveDashboard),
+		permissions: make(map[string][]*types.DashboardACLUpdateItem),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org := r.Header.Get(grafanahttp.OrgIDHeader)
+		if len(org) == 0 {
+			org = "1"
+		}
+		var response interface{}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/org":
+			response = &types.Org{ID: 1, Name: "Main"}
+		case "GET /api/dashboards/uid/source":
+			_, _ = w.Write([]byte(cloneTestDashboard))
+			return
+		case "GET /api/datasources":
+			if org == "1" {
+				response = []*types.DataSource{{UID: "prom-main", Name: "Prometheus", Type: "prometheus", IsDefault: true}}
+			} else {
+				response = []*types.DataSource{{UID: "prom-other", Name: "Prometheus", Type: "prometheus", IsDefault: true}}
+			}
+		case "POST /api/dashboards/db":
+			saved := &types.SaveDashboard{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(saved))
+			fake.saved[org] = append(fake.saved[org], saved)
+			response = &types.SimpleDashboard{UID: "copy", Version: 1, Status: "success"}
+		case "GET /api/dashboards/uid/source/permissions":
+			viewer := types.RoleViewer
+			response = []*types.FolderOrDashboardPermission{
+				{UserID: 3, Permission: types.PermissionEdit},
+				{TeamID: 4, Team: "ops", Permission: types.PermissionView},
+				{Role: &viewer, Permission: types.PermissionView, Inherited: true},
+			}
+		case "POST /api/dashboards/uid/copy/permissions":
+			body := struct {
+				Items []*types.DashboardACLUpdateItem `json:"items"`
+			}{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			fake.permissions[org] = body.Items
+			response = map[string]string{"message": "Dashboard permissions updated"}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	return fake, api.NewWithClient(rest), server.Close
+}
+
+func TestDashboard_SameOrg(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	result, err := Dashboard(client, "source", Target{FolderUID: "team-b"}, Options{
+		Message:     "copied",
+		Permissions: PreservePermissions,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "copy", result.Dashboard.UID)
+	assert.Empty(t, result.Warnings)
+
+	if assert.Len(t, fake.saved["1"], 1) {
+		saved := fake.saved["1"][0]
+		assert.Equal(t, "team-b", saved.FolderUID)
+		assert.Equal(t, "copied", saved.Message)
+		assert.Equal(t, "My dashboard", saved.Dashboard.Title())
+		// the copy is a new dashboard
+		assert.NotContains(t, saved.Dashboard, "id")
+		assert.NotContains(t, saved.Dashboard, "uid")
+		assert.NotContains(t, saved.Dashboard, "version")
+	}
+	// the inherited permissions are not copied
+	assert.Equal(t, []*types.DashboardACLUpdateItem{
+		{UserID: 3, Permission: types.PermissionEdit},
+		{TeamID: 4, Permission: types.PermissionView},
+	}, fake.permissions["1"])
+}
+
+func TestDashboard_CrossOrg(t *testing.T) {
+	fake, client, closeServer := newFakeGrafana(t)
+	defer closeServer()
+
+	result, err := Dashboard(client, "source", Target{OrgID: 2}, Options{
+		DatasourceMapping: dashboard.MapByName,
+		Permissions:       PreservePermissions,
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, fake.saved["1"])
+	if assert.Len(t, fake.saved["2"], 1) {
+		panel := dashboard.Panels(fake.saved["2"][0].Dashboard)[0]
+		assert.Equal(t, map[string]interface{}{"type": "prometheus", "uid": "prom-other"}, panel["datasource"])
+	}
+	// the teams only exist in their organisation
+	assert.Equal(t, []*types.DashboardACLUpdateItem{{UserID: 3, Permission: types.PermissionEdit}}, fake.permissions["2"])
+	assert.Equal(t, []string{"the permission of the team 'ops' can't be preserved in another organisation"}, result.Warnings)
+}
+
+func TestDashboard_Permissions(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		mode     PermissionMode
+		expected map[string][]*types.DashboardACLUpdateItem
+	}{
+		{
+			title:    "keep the default permissions",
+			mode:     KeepPermissions,
+			expected: map[string][]*types.DashboardACLUpdateItem{},
+		},
+		{
+			title:    "reset the permissions",
+			mode:     ResetPermissions,
+			expected: map[string][]*types.DashboardACLUpdateItem{"1": {}},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			fake, client, closeServer := newFakeGrafana(t)
+			defer closeServer()
+
+			_, err := Dashboard(client, "source", Target{FolderUID: "team-b"}, Options{Permissions: test.mode})
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, fake.permissions)
+		})
+	}
+}
+
+func TestDashboard_OverwriteSource(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		target   Target
+		options  Options
+		expected error
+	}{
+		{
+			title:    "same folder and same title",
+			target:   Target{FolderUID: "team-a"},
+			options:  Options{Overwrite: true},
+			expected: ErrOverwriteSource,
+		},
+		{
+			title:    "current organisation given explicitly",
+			target:   Target{OrgID: 1, FolderUID: "team-a"},
+			options:  Options{Overwrite: true, Title: "my dashboard"},
+			expected: ErrOverwriteSource,
+		},
+		{
+			title:    "same uid in another folder",
+			target:   Target{FolderUID: "team-b"},
+			options:  Options{Overwrite: true, UID: "source"},
+			expected: ErrOverwriteSource,
+		},
+		{
+			title:   "another title",
+			target:  Target{FolderUID: "team-a"},
+			options: Options{Overwrite: true, Title: "My dashboard (copy)"},
+		},
+		{
+			title:   "another organisation",
+			target:  Target{OrgID: 2, FolderUID: "team-a"},
+			options: Options{Overwrite: true},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			fake, client, closeServer := newFakeGrafana(t)
+			defer closeServer()
+
+			_, err := Dashboard(client, "source", test.target, test.options)
+			assert.Equal(t, test.expected, err)
+			if test.expected != nil {
+				assert.Empty(t, fake.saved)
+			}
+		})
+	}
+}
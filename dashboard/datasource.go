@@ -0,0 +1,184 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// DatasourceMapping tells how a datasource is matched with a datasource of another Grafana or organisation
+type DatasourceMapping string
+
+const (
+	// MapByName matches the datasource having the same name
+	MapByName DatasourceMapping = "name"
+	// MapByType matches the default datasource of the same type, or the first one of this type if none is the default one
+	MapByType DatasourceMapping = "type"
+)
+
+// DatasourceTarget is the new datasource set when a datasource is replaced.
+// Either Datasource or Variable must be set.
+type DatasourceTarget struct {
+	Datasource *types.DataSource
+	// Variable is the name, without $, of the template variable to use instead of the datasource
+	Variable string
+}
+
+// Validate checks that either the datasource or the variable is set
+func (t DatasourceTarget) Validate() error {
+	if t.Datasource == nil && len(t.Variable) == 0 {
+		return fmt.Errorf("the new datasource or the variable to use is missing")
+	}
+	return nil
+}
+
+// reference returns the new value of a field. The format of the old value is kept,
+// so a dashboard still using the legacy references (the name of the datasource) stays loadable by an old Grafana.
+func (t DatasourceTarget) reference(oldValue interface{}, oldType string) interface{} {
+	_, isObject := oldValue.(map[string]interface{})
+	if len(t.Variable) > 0 {
+		if isObject {
+			return map[string]interface{}{"type": oldType, "uid": "${" + t.Variable + "}"}
+		}
+		return "$" + t.Variable
+	}
+	if isObject {
+		return map[string]interface{}{"type": t.Datasource.Type, "uid": t.Datasource.UID}
+	}
+	return t.Datasource.Name
+}
+
+// ReplaceDatasource rewrites every reference to the datasource from (by name or by uid) in the panels, the queries,
+// the template variables and the annotations. The current value of the datasource variables is updated too
+// when the target is a datasource. It returns the paths of the fields modified.
+// The panels and the queries using the default datasource (no reference) are not modified.
+func ReplaceDatasource(model types.DashboardModel, from *types.DataSource, to DatasourceTarget) ([]string, error) {
+	if from == nil {
+		return nil, fmt.Errorf("the datasource to replace is missing")
+	}
+	if err := to.Validate(); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, field := range DatasourceFields(model) {
+		if !field.Ref.Matches(from) {
+			continue
+		}
+		oldType := field.Ref.Type
+		if len(oldType) == 0 {
+			oldType = from.Type
+		}
+		field.Set(to.reference(field.Value(), oldType))
+		paths = append(paths, field.Path)
+	}
+	if to.Datasource == nil {
+		return paths, nil
+	}
+	templating, _ := model["templating"].(map[string]interface{})
+	_ = eachObject(templating, "list", func(i int, variable map[string]interface{}) error {
+		if getString(variable, "type") != "datasource" {
+			return nil
+		}
+		current, _ := variable["current"].(map[string]interface{})
+		value := getString(current, "value")
+		if len(value) == 0 {
+			return nil
+		}
+		switch value {
+		case from.UID:
+			current["value"] = to.Datasource.UID
+		case from.Name:
+			current["value"] = to.Datasource.Name
+		default:
+			return nil
+		}
+		current["text"] = to.Datasource.Name
+		paths = append(paths, fmt.Sprintf("$.templating.list[%d].current", i))
+		return nil
+	})
+	return paths, nil
+}
+
+// RemapDatasources points the references to the datasources of source to the matching datasources of target.
+// The references to a variable or to a built-in datasource are not modified.
+// It returns the references that couldn't be remapped, either because the datasource doesn't exist in source
+// or because no datasource of target matches it.
+func RemapDatasources(model types.DashboardModel, source []*types.DataSource, target []*types.DataSource, mapping DatasourceMapping) ([]string, error) {
+	if mapping != MapByName && mapping != MapByType {
+		return nil, fmt.Errorf("unknown datasource mapping '%s'", mapping)
+	}
+	var unresolved []string
+	seen := make(map[string]bool)
+	addUnresolved := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			unresolved = append(unresolved, key)
+		}
+	}
+	for _, field := range DatasourceFields(model) {
+		if field.Ref.IsVariable() || field.Ref.IsBuiltIn() {
+			continue
+		}
+		from := findDatasource(field.Ref, source)
+		to := matchDatasource(from, target, mapping)
+		if to == nil {
+			addUnresolved(field.Ref.Key())
+			continue
+		}
+		field.Set(DatasourceTarget{Datasource: to}.reference(field.Value(), to.Type))
+	}
+	templating, _ := model["templating"].(map[string]interface{})
+	_ = eachObject(templating, "list", func(i int, variable map[string]interface{}) error {
+		if getString(variable, "type") != "datasource" {
+			return nil
+		}
+		current, _ := variable["current"].(map[string]interface{})
+		value := getString(current, "value")
+		if len(value) == 0 || strings.HasPrefix(value, "$") {
+			return nil
+		}
+		from := findDatasource(DatasourceRef{Name: value}, source)
+		to := matchDatasource(from, target, mapping)
+		if to == nil {
+			addUnresolved(value)
+			return nil
+		}
+		if value == from.UID {
+			current["value"] = to.UID
+		} else {
+			current["value"] = to.Name
+		}
+		current["text"] = to.Name
+		return nil
+	})
+	return unresolved, nil
+}
+
+func matchDatasource(from *types.DataSource, target []*types.DataSource, mapping DatasourceMapping) *types.DataSource {
+	if from == nil {
+		return nil
+	}
+	if mapping == MapByType {
+		return findDefaultDatasource(from.Type, target)
+	}
+	for _, ds := range target {
+		if ds.Name == from.Name {
+			return ds
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const replaceTestDashboard = `{
+  "uid": "abcd",
+  "panels": [
+    {"id": 1, "type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom-uid"},
+     "targets": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "prom-uid"}}, {"refId": "B"}]},
+    {"id": 2, "type": "row", "collapsed": true, "panels": [
+      {"id": 3, "type": "graph", "datasource": "Prometheus main"}
+    ]},
+    {"id": 4, "type": "stat", "datasource": {"type": "loki", "uid": "loki-uid"}}
+  ],
+  "templating": {"list": [
+    {"name": "ds", "type": "datasource", "query": "prometheus", "current": {"text": "Prometheus main", "value": "prom-uid"}},
+    {"name": "job", "type": "query", "datasource": {"type": "prometheus", "uid": "prom-uid"}}
+  ]},
+  "annotations": {"list": [
+    {"name": "deployments", "datasource": "Prometheus main"}
+  ]}
+}`
+
+func TestReplaceDatasource(t *testing.T) {
+	datasources := newTestDatasources()
+	from := datasources[0]
+
+	t.Run("replace by another datasource", func(t *testing.T) {
+		model := newTestDashboard(t, replaceTestDashboard)
+		paths, err := ReplaceDatasource(model, from, DatasourceTarget{Datasource: datasources[2]})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"$.panels[0].datasource",
+			"$.panels[0].targets[0].datasource",
+			"$.panels[1].panels[0].datasource",
+			"$.templating.list[1].datasource",
+			"$.annotations.list[0].datasource",
+			"$.templating.list[0].current",
+		}, paths)
+		panels := Panels(model)
+		assert.Equal(t, map[string]interface{}{"type": "prometheus", "uid": "prom-other"}, panels[0]["datasource"])
+		assert.Equal(t, "Prometheus other", panels[2]["datasource"])
+		assert.Equal(t, map[string]interface{}{"type": "loki", "uid": "loki-uid"}, panels[3]["datasource"])
+		assert.Equal(t, map[string]interface{}{"text": "Prometheus other", "value": "prom-other"}, Variables(model)[0]["current"])
+	})
+
+	t.Run("replace by a variable", func(t *testing.T) {
+		model := newTestDashboard(t, replaceTestDashboard)
+		paths, err := ReplaceDatasource(model, from, DatasourceTarget{Variable: "ds"})
+		assert.Nil(t, err)
+		assert.Len(t, paths, 5)
+		panels := Panels(model)
+		assert.Equal(t, map[string]interface{}{"type": "prometheus", "uid": "${ds}"}, Targets(panels[0])[0]["datasource"])
+		assert.Equal(t, "$ds", panels[2]["datasource"])
+		assert.Equal(t, map[string]interface{}{"text": "Prometheus main", "value": "prom-uid"}, Variables(model)[0]["current"])
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		_, err := ReplaceDatasource(newTestDashboard(t, replaceTestDashboard), from, DatasourceTarget{})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRemapDatasources(t *testing.T) {
+	source := newTestDatasources()
+	target := []*types.DataSource{
+		{ID: 10, UID: "prom-a", Name: "Prometheus other", Type: "prometheus"},
+		{ID: 11, UID: "prom-b", Name: "Prometheus default", Type: "prometheus", IsDefault: true},
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		model := newTestDashboard(t, replaceTestDashboard)
+		unresolved, err := RemapDatasources(model, source, target, MapByName)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"prom-uid", "Prometheus main", "loki-uid"}, unresolved)
+	})
+
+	t.Run("by type", func(t *testing.T) {
+		model := newTestDashboard(t, replaceTestDashboard)
+		unresolved, err := RemapDatasources(model, source, target, MapByType)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"loki-uid"}, unresolved)
+		panels := Panels(model)
+		assert.Equal(t, map[string]interface{}{"type": "prometheus", "uid": "prom-b"}, panels[0]["datasource"])
+		assert.Equal(t, "Prometheus default", panels[2]["datasource"])
+		assert.Equal(t, map[string]interface{}{"text": "Prometheus default", "value": "prom-b"}, Variables(model)[0]["current"])
+	})
+}
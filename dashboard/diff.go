@@ -0,0 +1,114 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Diff returns the differences between two dashboards.
+// Arrays are compared index by index, so inserting an element in the middle of an array is seen as
+// an update of every following element.
+func Diff(before types.DashboardModel, after types.DashboardModel) ([]*types.DashboardChange, error) {
+	beforeValue, err := toJSONValue(before)
+	if err != nil {
+		return nil, err
+	}
+	afterValue, err := toJSONValue(after)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*types.DashboardChange
+	diffValue("$", beforeValue, afterValue, &changes)
+	return changes, nil
+}
+
+func diffValue(path string, before interface{}, after interface{}, changes *[]*types.DashboardChange) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			diffObject(path, b, a, changes)
+			return
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			diffArray(path, b, a, changes)
+			return
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, &types.DashboardChange{Path: path, Type: types.DashboardChangeUpdated, Before: before, After: after})
+	}
+}
+
+func diffObject(path string, before map[string]interface{}, after map[string]interface{}, changes *[]*types.DashboardChange) {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, exist := before[key]; !exist {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := ChildPath(path, key)
+		beforeValue, inBefore := before[key]
+		afterValue, inAfter := after[key]
+		switch {
+		case !inAfter:
+			*changes = append(*changes, &types.DashboardChange{Path: childPath, Type: types.DashboardChangeRemoved, Before: beforeValue})
+		case !inBefore:
+			*changes = append(*changes, &types.DashboardChange{Path: childPath, Type: types.DashboardChangeAdded, After: afterValue})
+		default:
+			diffValue(childPath, beforeValue, afterValue, changes)
+		}
+	}
+}
+
+func diffArray(path string, before []interface{}, after []interface{}, changes *[]*types.DashboardChange) {
+	for i := 0; i < len(before) || i < len(after); i++ {
+		childPath := IndexPath(path, i)
+		switch {
+		case i >= len(after):
+			*changes = append(*changes, &types.DashboardChange{Path: childPath, Type: types.DashboardChangeRemoved, Before: before[i]})
+		case i >= len(before):
+			*changes = append(*changes, &types.DashboardChange{Path: childPath, Type: types.DashboardChangeAdded, After: after[i]})
+		default:
+			diffValue(childPath, before[i], after[i], changes)
+		}
+	}
+}
+
+// ChildPath returns the JSON path of the key in the object located at path
+func ChildPath(path string, key string) string {
+	if identifierRegexp.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "['" + strings.Replace(key, "'", "\\'", -1) + "']"
+}
+
+// IndexPath returns the JSON path of the element at the index in the array located at path
+func IndexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
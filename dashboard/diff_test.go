@@ -0,0 +1,34 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := newTestDashboard(t, `{"title": "my dashboard", "tags": ["prod", "team-a"], "panels": [{"id": 1, "title": "CPU"}], "a.b": 1}`)
+	after := newTestDashboard(t, `{"title": "my new dashboard", "tags": ["prod"], "panels": [{"id": 1, "title": "CPU", "description": "usage"}], "a.b": 2}`)
+	changes, err := Diff(before, after)
+	assert.Nil(t, err)
+	assert.Equal(t, []*types.DashboardChange{
+		{Path: "$['a.b']", Type: types.DashboardChangeUpdated, Before: float64(1), After: float64(2)},
+		{Path: "$.panels[0].description", Type: types.DashboardChangeAdded, After: "usage"},
+		{Path: "$.tags[1]", Type: types.DashboardChangeRemoved, Before: "team-a"},
+		{Path: "$.title", Type: types.DashboardChangeUpdated, Before: "my dashboard", After: "my new dashboard"},
+	}, changes)
+}
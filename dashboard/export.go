@@ -0,0 +1,245 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+const (
+	InputTypeDatasource = "datasource"
+	InputTypeConstant   = "constant"
+)
+
+var inputNameRegexp = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// ExportByUID fetches the dashboard and returns it in the format used to share it externally (see Export).
+// The datasources of the organisation and the version of Grafana are fetched as well to generate __inputs and __requires.
+func ExportByUID(client api.ClientInterface, uid string) (types.DashboardModel, error) {
+	result, err := client.Dashboards().GetByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	datasources, err := client.DataSources().Get()
+	if err != nil {
+		return nil, err
+	}
+	health, err := client.Health().Get()
+	if err != nil {
+		return nil, err
+	}
+	return Export(result.Dashboard, datasources, health.Version)
+}
+
+// Export transforms the dashboard into the format used by Grafana when a dashboard is exported for sharing externally.
+// The datasources referenced are replaced by inputs named ${DS_<NAME>}, the constant variables by inputs named ${VAR_<NAME>}
+// and the sections __inputs and __requires are generated.
+// datasources must contain every datasource referenced by the dashboard, otherwise an error is returned.
+// The dashboard passed as a parameter is not modified.
+func Export(model types.DashboardModel, datasources []*types.DataSource, grafanaVersion string) (types.DashboardModel, error) {
+	result, err := Copy(model)
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs []*types.DashboardInput
+	requires := map[string]*types.DashboardRequirement{
+		"grafana": {Type: "grafana", ID: "grafana", Name: "Grafana", Version: grafanaVersion},
+	}
+
+	// templateize every datasource usage
+	inputByDatasource := make(map[string]*types.DashboardInput)
+	for _, field := range DatasourceFields(result) {
+		if field.Ref.IsVariable() || field.Ref.IsBuiltIn() {
+			continue
+		}
+		ds := findDatasource(field.Ref, datasources)
+		if ds == nil {
+			return nil, fmt.Errorf("unable to find the datasource '%s' referenced by %s", field.Ref.Key(), field.Path)
+		}
+		input, exist := inputByDatasource[ds.UID+"/"+ds.Name]
+		if !exist {
+			pluginName := ds.TypeName
+			if len(pluginName) == 0 {
+				pluginName = ds.Type
+			}
+			input = &types.DashboardInput{
+				Name:       inputName("DS_", ds.Name),
+				Label:      ds.Name,
+				Type:       InputTypeDatasource,
+				PluginID:   ds.Type,
+				PluginName: pluginName,
+			}
+			inputByDatasource[ds.UID+"/"+ds.Name] = input
+			inputs = append(inputs, input)
+			requires["datasource/"+ds.Type] = &types.DashboardRequirement{Type: "datasource", ID: ds.Type, Name: pluginName}
+		}
+		if _, isObject := field.Value().(map[string]interface{}); isObject {
+			field.Set(map[string]interface{}{"type": ds.Type, "uid": "${" + input.Name + "}"})
+		} else {
+			field.Set("${" + input.Name + "}")
+		}
+	}
+
+	for _, panel := range Panels(result) {
+		panelType := PanelType(panel)
+		if len(panelType) == 0 || panelType == "row" {
+			continue
+		}
+		requires["panel/"+panelType] = &types.DashboardRequirement{Type: "panel", ID: panelType, Name: panelType}
+	}
+
+	for _, variable := range Variables(result) {
+		switch getString(variable, "type") {
+		case "query":
+			// the options depend on the datasource so they can't be exported
+			variable["options"] = []interface{}{}
+			variable["current"] = map[string]interface{}{}
+			if refresh := getInt64(variable, "refresh"); refresh == 0 {
+				// 1 means the variable is refreshed when the dashboard is loaded
+				variable["refresh"] = float64(1)
+			}
+		case "constant":
+			name := getString(variable, "name")
+			label := getString(variable, "label")
+			if len(label) == 0 {
+				label = name
+			}
+			input := &types.DashboardInput{
+				Name:  inputName("VAR_", name),
+				Label: label,
+				Type:  InputTypeConstant,
+				Value: getString(variable, "query"),
+			}
+			inputs = append(inputs, input)
+			value := "${" + input.Name + "}"
+			current := map[string]interface{}{"value": value, "text": value, "selected": false}
+			variable["query"] = value
+			variable["current"] = current
+			variable["options"] = []interface{}{current}
+		}
+	}
+
+	requireList := make([]*types.DashboardRequirement, 0, len(requires))
+	for _, require := range requires {
+		requireList = append(requireList, require)
+	}
+	sort.Slice(requireList, func(i, j int) bool {
+		if requireList[i].ID == requireList[j].ID {
+			return requireList[i].Type < requireList[j].Type
+		}
+		return requireList[i].ID < requireList[j].ID
+	})
+
+	if inputs == nil {
+		inputs = []*types.DashboardInput{}
+	}
+	if result["__inputs"], err = toJSONValue(inputs); err != nil {
+		return nil, err
+	}
+	if result["__requires"], err = toJSONValue(requireList); err != nil {
+		return nil, err
+	}
+	result["id"] = nil
+	return result, nil
+}
+
+// Inputs returns the content of the section __inputs of a dashboard exported for sharing externally.
+func Inputs(model types.DashboardModel) ([]*types.DashboardInput, error) {
+	var inputs []*types.DashboardInput
+	if _, exist := model["__inputs"]; !exist {
+		return inputs, nil
+	}
+	err := convert(model["__inputs"], &inputs)
+	return inputs, err
+}
+
+// ImportInputs builds the inputs required to import a dashboard exported for sharing externally.
+// values contains the value of the inputs, indexed by the name of the input.
+// For an input of type datasource, the value is the uid or the name of the datasource to use.
+// When no value is provided, the datasource is the default one of the plugin type required,
+// or the first one of this type if there is no default. A constant input falls back to the value set in the section __inputs.
+func ImportInputs(model types.DashboardModel, values map[string]string, datasources []*types.DataSource) ([]*types.ImportDashboardInput, error) {
+	inputs, err := Inputs(model)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*types.ImportDashboardInput, 0, len(inputs))
+	for _, input := range inputs {
+		importInput := &types.ImportDashboardInput{Name: input.Name, Type: input.Type, PluginID: input.PluginID}
+		value, hasValue := values[input.Name]
+		switch input.Type {
+		case InputTypeDatasource:
+			var ds *types.DataSource
+			if hasValue {
+				ds = findDatasource(DatasourceRef{Name: value}, datasources)
+			} else {
+				ds = findDefaultDatasource(input.PluginID, datasources)
+			}
+			if ds == nil {
+				return nil, fmt.Errorf("unable to find a datasource of type '%s' for the input %s", input.PluginID, input.Name)
+			}
+			if len(input.PluginID) > 0 && ds.Type != input.PluginID {
+				return nil, fmt.Errorf("the datasource '%s' used for the input %s has the type '%s' while '%s' is expected", ds.Name, input.Name, ds.Type, input.PluginID)
+			}
+			importInput.Value = ds.UID
+			if len(importInput.Value) == 0 {
+				// Grafana older than 7.0 doesn't know the uid of a datasource
+				importInput.Value = ds.Name
+			}
+		default:
+			importInput.Value = input.Value
+			if hasValue {
+				importInput.Value = value
+			}
+		}
+		result = append(result, importInput)
+	}
+	return result, nil
+}
+
+func findDatasource(ref DatasourceRef, datasources []*types.DataSource) *types.DataSource {
+	for _, ds := range datasources {
+		if ref.Matches(ds) {
+			return ds
+		}
+	}
+	return nil
+}
+
+func findDefaultDatasource(pluginID string, datasources []*types.DataSource) *types.DataSource {
+	var result *types.DataSource
+	for _, ds := range datasources {
+		if ds.Type != pluginID {
+			continue
+		}
+		if ds.IsDefault {
+			return ds
+		}
+		if result == nil {
+			result = ds
+		}
+	}
+	return result
+}
+
+func inputName(prefix string, name string) string {
+	return prefix + inputNameRegexp.ReplaceAllString(strings.ToUpper(name), "_")
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+const exportTestDashboard = `{
+  "id": 42,
+  "uid": "abcd",
+  "title": "my dashboard",
+  "panels": [
+    {"id": 1, "type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom-uid"},
+     "targets": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "prom-uid"}, "expr": "up"}]},
+    {"id": 2, "type": "row", "collapsed": true, "panels": [
+      {"id": 3, "type": "graph", "datasource": "Loki logs", "targets": [{"refId": "A"}]}
+    ]},
+    {"id": 4, "type": "text", "datasource": {"type": "datasource", "uid": "grafana"}},
+    {"id": 5, "type": "stat", "datasource": "$ds"}
+  ],
+  "templating": {"list": [
+    {"name": "ds", "type": "datasource", "query": "prometheus"},
+    {"name": "job", "type": "query", "refresh": 0, "datasource": {"type": "prometheus", "uid": "prom-uid"},
+     "current": {"text": "node", "value": "node"}, "options": [{"text": "node", "value": "node"}]},
+    {"name": "env", "type": "constant", "query": "production"}
+  ]},
+  "annotations": {"list": [
+    {"name": "Annotations & Alerts", "builtIn": 1, "datasource": "-- Grafana --"}
+  ]}
+}`
+
+func newTestDatasources() []*types.DataSource {
+	return []*types.DataSource{
+		{ID: 1, UID: "prom-uid", Name: "Prometheus main", Type: "prometheus", TypeName: "Prometheus", IsDefault: true},
+		{ID: 2, UID: "loki-uid", Name: "Loki logs", Type: "loki", TypeName: "Loki"},
+		{ID: 3, UID: "prom-other", Name: "Prometheus other", Type: "prometheus", TypeName: "Prometheus"},
+	}
+}
+
+func newTestDashboard(t *testing.T, content string) types.DashboardModel {
+	model := make(types.DashboardModel)
+	assert.Nil(t, json.Unmarshal([]byte(content), &model))
+	return model
+}
+
+func TestExport(t *testing.T) {
+	model := newTestDashboard(t, exportTestDashboard)
+	result, err := Export(model, newTestDatasources(), "9.5.2")
+	assert.Nil(t, err)
+
+	// the initial dashboard must not be modified
+	assert.Equal(t, float64(42), model["id"])
+
+	assert.Nil(t, result["id"])
+	panels := Panels(result)
+	assert.Equal(t, map[string]interface{}{"type": "prometheus", "uid": "${DS_PROMETHEUS_MAIN}"}, panels[0]["datasource"])
+	assert.Equal(t, map[string]interface{}{"type": "prometheus", "uid": "${DS_PROMETHEUS_MAIN}"}, Targets(panels[0])[0]["datasource"])
+	assert.Equal(t, "${DS_LOKI_LOGS}", panels[2]["datasource"])
+	assert.Equal(t, map[string]interface{}{"type": "datasource", "uid": "grafana"}, panels[3]["datasource"])
+	assert.Equal(t, "$ds", panels[4]["datasource"])
+
+	variables := Variables(result)
+	assert.Equal(t, []interface{}{}, variables[1]["options"])
+	assert.Equal(t, map[string]interface{}{}, variables[1]["current"])
+	assert.Equal(t, float64(1), variables[1]["refresh"])
+	assert.Equal(t, "${VAR_ENV}", variables[2]["query"])
+
+	inputs, err := Inputs(result)
+	assert.Nil(t, err)
+	assert.Equal(t, []*types.DashboardInput{
+		{Name: "DS_PROMETHEUS_MAIN", Label: "Prometheus main", Type: "datasource", PluginID: "prometheus", PluginName: "Prometheus"},
+		{Name: "DS_LOKI_LOGS", Label: "Loki logs", Type: "datasource", PluginID: "loki", PluginName: "Loki"},
+		{Name: "VAR_ENV", Label: "env", Type: "constant", Value: "production"},
+	}, inputs)
+
+	var requires []*types.DashboardRequirement
+	assert.Nil(t, convert(result["__requires"], &requires))
+	assert.Equal(t, []*types.DashboardRequirement{
+		{Type: "grafana", ID: "grafana", Name: "Grafana", Version: "9.5.2"},
+		{Type: "panel", ID: "graph", Name: "graph"},
+		{Type: "datasource", ID: "loki", Name: "Loki"},
+		{Type: "datasource", ID: "prometheus", Name: "Prometheus"},
+		{Type: "panel", ID: "stat", Name: "stat"},
+		{Type: "panel", ID: "text", Name: "text"},
+		{Type: "panel", ID: "timeseries", Name: "timeseries"},
+	}, requires)
+}
+
+func TestExportByUID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/dashboards/uid/abcd":
+			_, _ = w.Write([]byte(`{"meta": {}, "dashboard": ` + exportTestDashboard + `}`))
+		case "/api/datasources":
+			assert.Nil(t, json.NewEncoder(w).Encode(newTestDatasources()))
+		case "/api/health":
+			_, _ = w.Write([]byte(`{"commit": "abc", "database": "ok", "version": "10.4.1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	result, err := ExportByUID(api.NewWithClient(rest), "abcd")
+	assert.Nil(t, err)
+
+	expected, err := Export(newTestDashboard(t, exportTestDashboard), newTestDatasources(), "10.4.1")
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestExport_UnknownDatasource(t *testing.T) {
+	model := newTestDashboard(t, `{"panels": [{"id": 1, "type": "graph", "datasource": "unknown"}]}`)
+	_, err := Export(model, newTestDatasources(), "9.5.2")
+	assert.NotNil(t, err)
+}
+
+func TestImportInputs(t *testing.T) {
+	model := newTestDashboard(t, exportTestDashboard)
+	exported, err := Export(model, newTestDatasources(), "9.5.2")
+	assert.Nil(t, err)
+
+	testSuites := []struct {
+		title         string
+		values        map[string]string
+		result        []*types.ImportDashboardInput
+		expectedError bool
+	}{
+		{
+			title: "default values",
+			result: []*types.ImportDashboardInput{
+				{Name: "DS_PROMETHEUS_MAIN", Type: "datasource", PluginID: "prometheus", Value: "prom-uid"},
+				{Name: "DS_LOKI_LOGS", Type: "datasource", PluginID: "loki", Value: "loki-uid"},
+				{Name: "VAR_ENV", Type: "constant", Value: "production"},
+			},
+		},
+		{
+			title:  "values overridden",
+			values: map[string]string{"DS_PROMETHEUS_MAIN": "Prometheus other", "VAR_ENV": "staging"},
+			result: []*types.ImportDashboardInput{
+				{Name: "DS_PROMETHEUS_MAIN", Type: "datasource", PluginID: "prometheus", Value: "prom-other"},
+				{Name: "DS_LOKI_LOGS", Type: "datasource", PluginID: "loki", Value: "loki-uid"},
+				{Name: "VAR_ENV", Type: "constant", Value: "staging"},
+			},
+		},
+		{
+			title:         "datasource with the wrong type",
+			values:        map[string]string{"DS_PROMETHEUS_MAIN": "loki-uid"},
+			expectedError: true,
+		},
+	}
+
+	for _, testSuite := range testSuites {
+		result, err := ImportInputs(exported, testSuite.values, newTestDatasources())
+		assert.Equal(t, testSuite.expectedError, err != nil, testSuite.title)
+		assert.Equal(t, testSuite.result, result, testSuite.title)
+	}
+}
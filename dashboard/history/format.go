@@ -0,0 +1,63 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const dateFormat = "2006-01-02"
+
+// WriteMarkdown writes the report in Markdown, ready to be posted to the teams:
+// a summary per author followed by the changes of every dashboard
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Dashboard changes from %s to %s\n\n", r.Since.Format(dateFormat), r.Until.Format(dateFormat))
+	if len(r.Dashboards) == 0 {
+		b.WriteString("No dashboard has been changed.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("| Author | Dashboards | Changes |\n| --- | --- | --- |\n")
+	for _, summary := range r.Authors() {
+		fmt.Fprintf(&b, "| %s | %d | %d |\n", escape(summary.Author), summary.Dashboards, summary.Changes)
+	}
+
+	for _, d := range r.Dashboards {
+		title := d.Title
+		if len(d.FolderTitle) > 0 {
+			title = d.FolderTitle + " / " + d.Title
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		for _, change := range d.Changes {
+			fmt.Fprintf(&b, "- v%d by %s on %s", change.Version, change.Author, change.Created.Format(dateFormat))
+			if change.RestoredFrom > 0 {
+				fmt.Fprintf(&b, ", restored from v%d", change.RestoredFrom)
+			}
+			if len(change.Message) > 0 {
+				fmt.Fprintf(&b, ": %s", strings.Replace(change.Message, "\n", " ", -1))
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escape(cell string) string {
+	return strings.Replace(cell, "|", "\\|", -1)
+}
@@ -0,0 +1,208 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history analyses the versions of the dashboards of an organisation: which dashboards changed during
+// a period and who changed them, who edited each panel last, and which versions exceed a retention limit.
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// versionsPerPage is the number of versions requested at once
+const versionsPerPage = 100
+
+// Change is a version of a dashboard
+type Change struct {
+	Version int       `json:"version"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Message string    `json:"message,omitempty"`
+	// RestoredFrom is the version restored by this change, 0 if it's a regular modification
+	RestoredFrom int `json:"restoredFrom,omitempty"`
+}
+
+// DashboardChanges are the changes of a dashboard during the period, the most recent first
+type DashboardChanges struct {
+	UID         string    `json:"uid"`
+	Title       string    `json:"title"`
+	FolderTitle string    `json:"folderTitle,omitempty"`
+	Changes     []*Change `json:"changes"`
+	// Authors are the users who changed the dashboard during the period, sorted
+	Authors []string `json:"authors"`
+}
+
+// AuthorSummary is the activity of a user during the period
+type AuthorSummary struct {
+	Author     string `json:"author"`
+	Dashboards int    `json:"dashboards"`
+	Changes    int    `json:"changes"`
+}
+
+// Report lists the dashboards changed during a period
+type Report struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+	// Dashboards are sorted by folder and then by title
+	Dashboards []*DashboardChanges `json:"dashboards"`
+}
+
+// Authors summarizes the activity of every user, the most active first
+func (r *Report) Authors() []*AuthorSummary {
+	summaries := make(map[string]*AuthorSummary)
+	for _, d := range r.Dashboards {
+		for _, change := range d.Changes {
+			summary, exist := summaries[change.Author]
+			if !exist {
+				summary = &AuthorSummary{Author: change.Author}
+				summaries[change.Author] = summary
+			}
+			summary.Changes++
+		}
+		for _, author := range d.Authors {
+			summaries[author].Dashboards++
+		}
+	}
+	result := make([]*AuthorSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Changes != result[j].Changes {
+			return result[i].Changes > result[j].Changes
+		}
+		return result[i].Author < result[j].Author
+	})
+	return result
+}
+
+// Changes lists the dashboards matching the query that have been changed between since and until.
+// A zero until means now. Only the versions of the period are requested, the most recent first.
+func Changes(client api.ClientInterface, query api.QueryParameterSearch, since time.Time, until time.Time) (*Report, error) {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	query.SearchType = types.SearchDashboardType
+	hits, err := client.Search().QueryAll(query)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Since: since, Until: until}
+	for _, hit := range hits {
+		versions, err := versionsSince(client, hit.UID, since)
+		if err != nil {
+			return nil, err
+		}
+		changes := changesBetween(versions, since, until)
+		if len(changes) == 0 {
+			continue
+		}
+		report.Dashboards = append(report.Dashboards, &DashboardChanges{
+			UID:         hit.UID,
+			Title:       hit.Title,
+			FolderTitle: hit.FolderTitle,
+			Changes:     changes,
+			Authors:     authors(changes),
+		})
+	}
+	sort.SliceStable(report.Dashboards, func(i, j int) bool {
+		a, b := report.Dashboards[i], report.Dashboards[j]
+		if a.FolderTitle != b.FolderTitle {
+			return a.FolderTitle < b.FolderTitle
+		}
+		return a.Title < b.Title
+	})
+	return report, nil
+}
+
+// versionsSince returns the versions of the dashboard, the most recent first, stopping at the first one created before since
+func versionsSince(client api.ClientInterface, uid string, since time.Time) ([]*types.DashboardVersion, error) {
+	var result []*types.DashboardVersion
+	err := eachVersionPage(client, uid, func(versions []*types.DashboardVersion) bool {
+		result = append(result, versions...)
+		return !versions[len(versions)-1].Created.Before(since)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// eachVersionPage calls fn with every page of versions of the dashboard, the most recent first, until fn returns false.
+// The pages are requested with the continue token when Grafana provides one, with the start parameter otherwise.
+// A page without any version not seen yet stops the walk, so a server ignoring the paging can't make it loop forever.
+func eachVersionPage(client api.ClientInterface, uid string, fn func(versions []*types.DashboardVersion) bool) error {
+	query := api.QueryParameterDashboardVersions{Limit: versionsPerPage}
+	seen := make(map[int]bool)
+	for {
+		page, err := client.Dashboards().GetVersionsByUID(uid, query)
+		if err != nil {
+			return err
+		}
+		var versions []*types.DashboardVersion
+		for _, version := range page.Versions {
+			if !seen[version.Version] {
+				seen[version.Version] = true
+				versions = append(versions, version)
+			}
+		}
+		if len(versions) == 0 || !fn(versions) {
+			return nil
+		}
+		switch {
+		case len(page.ContinueToken) > 0:
+			query.ContinueToken = page.ContinueToken
+		case len(query.ContinueToken) > 0 || len(page.Versions) < query.Limit:
+			// the last page of a paging by token has no token
+			return nil
+		default:
+			query.Start += len(page.Versions)
+		}
+	}
+}
+
+// changesBetween keeps the versions created in the period, the most recent first
+func changesBetween(versions []*types.DashboardVersion, since time.Time, until time.Time) []*Change {
+	var changes []*Change
+	for _, version := range versions {
+		if version.Created.Before(since) || version.Created.After(until) {
+			continue
+		}
+		changes = append(changes, &Change{
+			Version:      version.Version,
+			Author:       version.CreatedBy,
+			Created:      version.Created,
+			Message:      version.Message,
+			RestoredFrom: version.RestoredFrom,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Version > changes[j].Version })
+	return changes
+}
+
+func authors(changes []*Change) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, change := range changes {
+		if !seen[change.Author] {
+			seen[change.Author] = true
+			result = append(result, change.Author)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
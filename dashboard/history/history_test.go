@@ -0,0 +1,296 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVersion(t *testing.T, version int, author string, data string) *types.DashboardVersion {
+	result := &types.DashboardVersion{
+		Version:   version,
+		CreatedBy: author,
+		Created:   time.Date(2024, 3, version, 10, 0, 0, 0, time.UTC),
+	}
+	assert.Nil(t, json.Unmarshal([]byte(data), &result.Data))
+	return result
+}
+
+func TestAttributePanels(t *testing.T) {
+	versions := []*types.DashboardVersion{
+		newTestVersion(t, 3, "carol", `{"panels": [
+      {"id": 1, "title": "CPU", "type": "timeseries"},
+      {"id": 3, "title": "Disk", "type": "stat"}
+    ]}`),
+		newTestVersion(t, 1, "alice", `{"panels": [
+      {"id": 1, "title": "CPU", "type": "graph"},
+      {"id": 2, "title": "Memory", "type": "graph"}
+    ]}`),
+		newTestVersion(t, 2, "bob", `{"panels": [
+      {"id": 1, "title": "CPU", "type": "timeseries"},
+      {"id": 2, "title": "Memory", "type": "graph"}
+    ]}`),
+	}
+	editors, err := AttributePanels(versions)
+	assert.Nil(t, err)
+	assert.Equal(t, []*PanelEditor{
+		{PanelID: 1, Title: "CPU", Editor: "bob", Version: 2, Updated: versions[2].Created},
+		{PanelID: 3, Title: "Disk", Editor: "carol", Version: 3, Updated: versions[0].Created},
+	}, editors)
+
+	_, err = AttributePanels([]*types.DashboardVersion{{Version: 1}})
+	assert.NotNil(t, err)
+}
+
+func TestAttributePanels_RowsAndPanelsWithoutID(t *testing.T) {
+	versions := []*types.DashboardVersion{
+		newTestVersion(t, 1, "alice", `{"panels": [
+      {"id": 1, "title": "Row", "type": "row", "collapsed": true, "panels": [
+        {"id": 2, "title": "CPU", "type": "graph"}
+      ]},
+      {"title": "no id", "type": "text"}
+    ]}`),
+		newTestVersion(t, 2, "bob", `{"panels": [
+      {"id": 1, "title": "Row", "type": "row", "collapsed": true, "panels": [
+        {"id": 2, "title": "CPU", "type": "timeseries"}
+      ]},
+      {"title": "no id", "type": "text", "options": {"content": "changed"}},
+      {"title": "another one without id", "type": "text"}
+    ]}`),
+	}
+	editors, err := AttributePanels(versions)
+	assert.Nil(t, err)
+	// the row isn't modified by the modification of the panel it holds and the panels without id are ignored
+	assert.Equal(t, []*PanelEditor{
+		{PanelID: 1, Title: "Row", Editor: "alice", Version: 1, Updated: versions[0].Created},
+		{PanelID: 2, Title: "CPU", Editor: "bob", Version: 2, Updated: versions[1].Created},
+	}, editors)
+}
+
+// newVersionsServer returns a fake Grafana holding the dashboards with the given number of versions.
+// The version n has been created n hours after 2024-03-01.
+// versionsPaging is how the fake Grafana pages the versions
+type versionsPaging int
+
+const (
+	// startPaging returns a list and honors the start parameter, like Grafana < 11
+	startPaging versionsPaging = iota
+	// tokenPaging returns the versions with a continue token and ignores the start parameter, like Grafana >= 11
+	tokenPaging
+	// brokenPaging always returns the first page with the same continue token
+	brokenPaging
+)
+
+func newVersionsServer(t *testing.T, totals map[string]int, paging versionsPaging, requests *[]string) (api.ClientInterface, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/search" {
+			var hits []*types.SearchResult
+			for _, uid := range []string{"a", "b"} {
+				if _, exist := totals[uid]; exist {
+					hits = append(hits, &types.SearchResult{UID: uid, Title: strings.ToUpper(uid)})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(hits)
+			return
+		}
+		uid := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/dashboards/uid/"), "/versions")
+		total, exist := totals[uid]
+		if !exist {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		*requests = append(*requests, uid+"?"+r.URL.RawQuery)
+		var start int
+		switch paging {
+		case startPaging:
+			start, _ = strconv.Atoi(r.URL.Query().Get("start"))
+		case tokenPaging:
+			start, _ = strconv.Atoi(strings.TrimPrefix(r.URL.Query().Get("continueToken"), "offset-"))
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		versions := []*types.DashboardVersion{}
+		for version := total - start; version > 0 && len(versions) < limit; version-- {
+			versions = append(versions, &types.DashboardVersion{
+				Version:   version,
+				CreatedBy: "alice",
+				Created:   time.Date(2024, 3, 1, version, 0, 0, 0, time.UTC),
+			})
+		}
+		switch paging {
+		case startPaging:
+			_ = json.NewEncoder(w).Encode(versions)
+		case tokenPaging:
+			list := &types.DashboardVersionList{Versions: versions}
+			if start+len(versions) < total {
+				list.ContinueToken = "offset-" + strconv.Itoa(start+len(versions))
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		case brokenPaging:
+			_ = json.NewEncoder(w).Encode(&types.DashboardVersionList{Versions: versions, ContinueToken: "same"})
+		}
+	}))
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	return api.NewWithClient(rest), server.Close
+}
+
+func TestChanges(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		paging   versionsPaging
+		requests []string
+	}{
+		{
+			title:    "paging with start",
+			paging:   startPaging,
+			requests: []string{"a?limit=100", "a?limit=100&start=100"},
+		},
+		{
+			title:    "paging with a continue token",
+			paging:   tokenPaging,
+			requests: []string{"a?limit=100", "a?continueToken=offset-100&limit=100"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			var requests []string
+			client, closeServer := newVersionsServer(t, map[string]int{"a": 250}, test.paging, &requests)
+			defer closeServer()
+
+			since := time.Date(2024, 3, 1, 120, 0, 0, 0, time.UTC)
+			until := time.Date(2024, 3, 1, 200, 0, 0, 0, time.UTC)
+			report, err := Changes(client, api.QueryParameterSearch{}, since, until)
+			assert.Nil(t, err)
+			if assert.Len(t, report.Dashboards, 1) {
+				changes := report.Dashboards[0].Changes
+				assert.Len(t, changes, 81)
+				assert.Equal(t, 200, changes[0].Version)
+				assert.Equal(t, 120, changes[len(changes)-1].Version)
+			}
+			// the paging stops with the first page reaching a version older than the period
+			assert.Equal(t, test.requests, requests)
+		})
+	}
+}
+
+func TestPrunePlan(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		paging   versionsPaging
+		requests []string
+	}{
+		{
+			title:    "paging with start",
+			paging:   startPaging,
+			requests: []string{"a?limit=100", "a?limit=100&start=100", "b?limit=100"},
+		},
+		{
+			title:    "paging with a continue token",
+			paging:   tokenPaging,
+			requests: []string{"a?limit=100", "a?continueToken=offset-100&limit=100", "b?limit=100"},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			var requests []string
+			client, closeServer := newVersionsServer(t, map[string]int{"a": 150, "b": 5}, test.paging, &requests)
+			defer closeServer()
+
+			candidates, err := PrunePlan(client, api.QueryParameterSearch{}, 10)
+			assert.Nil(t, err)
+			if assert.Len(t, candidates, 1) {
+				assert.Equal(t, "a", candidates[0].UID)
+				assert.Len(t, candidates[0].Versions, 140)
+				assert.Equal(t, 140, candidates[0].Versions[0])
+				assert.Equal(t, 1, candidates[0].Versions[139])
+			}
+			assert.Equal(t, test.requests, requests)
+
+			_, err = PrunePlan(client, api.QueryParameterSearch{}, 0)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestPrunePlan_PagingNotAdvancing(t *testing.T) {
+	var requests []string
+	client, closeServer := newVersionsServer(t, map[string]int{"a": 150}, brokenPaging, &requests)
+	defer closeServer()
+
+	// the server returns the first page forever, the walk stops once it doesn't get any new version
+	candidates, err := PrunePlan(client, api.QueryParameterSearch{}, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, candidates, 1) {
+		assert.Len(t, candidates[0].Versions, 90)
+	}
+	assert.Equal(t, []string{"a?limit=100", "a?continueToken=same&limit=100"}, requests)
+
+	report, err := Changes(client, api.QueryParameterSearch{}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	if assert.Len(t, report.Dashboards, 1) {
+		assert.Len(t, report.Dashboards[0].Changes, 100)
+	}
+}
+
+func TestReport(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	versions := []*types.DashboardVersion{
+		{Version: 4, CreatedBy: "bob", Created: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{Version: 3, CreatedBy: "alice", Created: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), Message: "fix\nthreshold"},
+		{Version: 2, CreatedBy: "bob", Created: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), RestoredFrom: 1},
+		{Version: 1, CreatedBy: "alice", Created: time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	changes := changesBetween(versions, since, until)
+	report := &Report{Since: since, Until: until, Dashboards: []*DashboardChanges{
+		{UID: "api", Title: "API", FolderTitle: "Ops", Changes: changes, Authors: authors(changes)},
+		{UID: "db", Title: "DB", Changes: []*Change{{Version: 7, Author: "alice", Created: since}}, Authors: []string{"alice"}},
+	}}
+	assert.Equal(t, []*AuthorSummary{
+		{Author: "alice", Dashboards: 2, Changes: 2},
+		{Author: "bob", Dashboards: 1, Changes: 1},
+	}, report.Authors())
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(t, report.WriteMarkdown(buffer))
+	assert.Equal(t, `# Dashboard changes from 2024-03-01 to 2024-03-08
+
+| Author | Dashboards | Changes |
+| --- | --- | --- |
+| alice | 2 | 2 |
+| bob | 1 | 1 |
+
+## Ops / API
+
+- v3 by alice on 2024-03-05: fix threshold
+- v2 by bob on 2024-03-02, restored from v1
+
+## DB
+
+- v7 by alice on 2024-03-01
+`, buffer.String())
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+// PanelEditor tells who modified a panel last
+type PanelEditor struct {
+	PanelID int64  `json:"panelId"`
+	Title   string `json:"title"`
+	Editor  string `json:"editor"`
+	// Version is the version of the dashboard in which the panel has been modified last
+	Version int       `json:"version"`
+	Updated time.Time `json:"updated"`
+}
+
+// AttributePanels compares the successive versions of a dashboard to find who modified last each panel
+// of the most recent version. The versions must contain their model, as returned by GetVersionByUID.
+// A panel is identified by its id and is considered modified when any of its properties changed, including its position.
+// The panels without id can't be followed from a version to another, they are ignored.
+// A row is compared without the panels it holds, so modifying a panel of a collapsed row isn't attributed to the row.
+// When the oldest version given isn't the first version of the dashboard, the panels that didn't change since then
+// are attributed to the author of this oldest version.
+func AttributePanels(versions []*types.DashboardVersion) ([]*PanelEditor, error) {
+	sorted := make([]*types.DashboardVersion, len(versions))
+	copy(sorted, versions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	editors := make(map[int64]*PanelEditor)
+	previous := make(map[int64]string)
+	for _, version := range sorted {
+		model, isObject := version.Data.(map[string]interface{})
+		if !isObject {
+			return nil, fmt.Errorf("the version %d doesn't contain the model of the dashboard", version.Version)
+		}
+		current := make(map[int64]string)
+		currentEditors := make(map[int64]*PanelEditor)
+		for _, panel := range dashboard.Panels(model) {
+			id := dashboard.PanelID(panel)
+			if id == 0 {
+				continue
+			}
+			data, err := json.Marshal(withoutNestedPanels(panel))
+			if err != nil {
+				return nil, err
+			}
+			current[id] = string(data)
+			title, _ := panel["title"].(string)
+			editor, exist := editors[id]
+			if !exist || previous[id] != current[id] {
+				editor = &PanelEditor{PanelID: id, Editor: version.CreatedBy, Version: version.Version, Updated: version.Created}
+			}
+			editor.Title = title
+			currentEditors[id] = editor
+		}
+		// a panel removed and then added again is considered as a new panel
+		editors = currentEditors
+		previous = current
+	}
+
+	result := make([]*PanelEditor, 0, len(editors))
+	for _, editor := range editors {
+		result = append(result, editor)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PanelID < result[j].PanelID })
+	return result, nil
+}
+
+// withoutNestedPanels returns the panel without the panels it holds when it's a collapsed row
+func withoutNestedPanels(panel map[string]interface{}) map[string]interface{} {
+	if _, hasPanels := panel["panels"]; !hasPanels {
+		return panel
+	}
+	result := make(map[string]interface{}, len(panel))
+	for key, value := range panel {
+		if key != "panels" {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// PanelEditors finds who modified last each panel of the dashboard, walking through at most maxVersions versions.
+// Every version walked is requested individually to get its model, so maxVersions should be kept small.
+func PanelEditors(client api.ClientInterface, uid string, maxVersions int) ([]*PanelEditor, error) {
+	if maxVersions <= 0 {
+		return nil, fmt.Errorf("the maximum number of versions must be positive")
+	}
+	summaries, err := client.Dashboards().GetVersionsByUID(uid, api.QueryParameterDashboardVersions{Limit: maxVersions})
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*types.DashboardVersion, 0, len(summaries.Versions))
+	for _, summary := range summaries.Versions {
+		version, err := client.Dashboards().GetVersionByUID(uid, summary.Version)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return AttributePanels(versions)
+}
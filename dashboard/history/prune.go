@@ -0,0 +1,68 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// PruneCandidate lists the versions of a dashboard exceeding the number of versions to keep
+type PruneCandidate struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+	// Versions are the versions beyond the most recent ones to keep, the most recent first
+	Versions []int `json:"versions"`
+}
+
+// PrunePlan finds, for every dashboard matching the query, the versions beyond the keep most recent ones.
+//
+// The HTTP API of Grafana doesn't allow to delete a version, so the plan can't be applied by the client.
+// Grafana removes the old versions by itself according to the setting versions_to_keep of the section [dashboards]
+// of its configuration, so the plan helps to choose this value and to know what it would remove.
+func PrunePlan(client api.ClientInterface, query api.QueryParameterSearch, keep int) ([]*PruneCandidate, error) {
+	if keep < 1 {
+		return nil, fmt.Errorf("at least one version must be kept")
+	}
+	query.SearchType = types.SearchDashboardType
+	hits, err := client.Search().QueryAll(query)
+	if err != nil {
+		return nil, err
+	}
+	var result []*PruneCandidate
+	for _, hit := range hits {
+		candidate := &PruneCandidate{UID: hit.UID, Title: hit.Title}
+		// the most recent versions are skipped here rather than with the start parameter, which is ignored
+		// by Grafana when it pages with a continue token
+		walked := 0
+		err := eachVersionPage(client, hit.UID, func(versions []*types.DashboardVersion) bool {
+			for _, version := range versions {
+				if walked >= keep {
+					candidate.Versions = append(candidate.Versions, version.Version)
+				}
+				walked++
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(candidate.Versions) > 0 {
+			result = append(result, candidate)
+		}
+	}
+	return result, nil
+}
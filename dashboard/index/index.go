@@ -0,0 +1,252 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package index provides a client-side full-text index of the content of the dashboards.
+// Unlike the search API that only matches the titles and the tags, it allows to find the panels using a metric,
+// a variable or a datasource.
+package index
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+// EntryKind tells which content of the dashboard an entry holds
+type EntryKind string
+
+const (
+	DashboardTitleEntry EntryKind = "dashboard-title"
+	PanelTitleEntry     EntryKind = "panel-title"
+	QueryEntry          EntryKind = "query"
+	VariableEntry       EntryKind = "variable"
+	DatasourceEntry     EntryKind = "datasource"
+)
+
+// queryFields are the fields of a target holding the query expression, depending on the datasource
+var queryFields = []string{"expr", "query", "rawSql", "target", "queryText"}
+
+// Entry is a text indexed
+type Entry struct {
+	DashboardUID   string `json:"dashboardUid"`
+	DashboardTitle string `json:"dashboardTitle"`
+	// PanelID is the id of the panel holding the text. It's 0 when the text doesn't belong to a panel.
+	PanelID int64     `json:"panelId,omitempty"`
+	Kind    EntryKind `json:"kind"`
+	// Path is the JSON path of the text, like $.panels[2].targets[0].expr
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// Query describes what is searched in the index
+type Query struct {
+	// Pattern is a substring, or a regular expression when Regexp is true
+	Pattern    string
+	Regexp     bool
+	IgnoreCase bool
+	// Kinds restricts the search to some kinds of entry. Every kind is searched when it's empty.
+	Kinds []EntryKind
+}
+
+// UsesVariable returns the query matching every text using the variable, whatever the syntax used
+// ($name, ${name}, ${name:format} or [[name]])
+func UsesVariable(name string) Query {
+	return Query{
+		Pattern: dashboard.VariableReferencePattern(name),
+		Regexp:  true,
+	}
+}
+
+func (q Query) matcher() (func(string) bool, error) {
+	if q.Regexp {
+		pattern := q.Pattern
+		if q.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	if q.IgnoreCase {
+		pattern := strings.ToLower(q.Pattern)
+		return func(value string) bool {
+			return strings.Contains(strings.ToLower(value), pattern)
+		}, nil
+	}
+	return func(value string) bool {
+		return strings.Contains(value, q.Pattern)
+	}, nil
+}
+
+func (q Query) acceptKind(kind EntryKind) bool {
+	if len(q.Kinds) == 0 {
+		return true
+	}
+	for _, k := range q.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+type document struct {
+	version int
+	entries []*Entry
+}
+
+// Index holds the content of the dashboards. It's safe for concurrent use.
+type Index struct {
+	mutex     sync.RWMutex
+	documents map[string]*document
+}
+
+// New returns an empty index
+func New() *Index {
+	return &Index{documents: make(map[string]*document)}
+}
+
+// Add indexes the dashboard, replacing its previous content if it's already indexed.
+// The uid and the version are read from the model.
+func (idx *Index) Add(model types.DashboardModel) error {
+	uid := model.UID()
+	if len(uid) == 0 {
+		return fmt.Errorf("the dashboard has no uid")
+	}
+	doc := &document{version: model.Version(), entries: extract(uid, model)}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	idx.documents[uid] = doc
+	return nil
+}
+
+// Remove removes the dashboard from the index
+func (idx *Index) Remove(uid string) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	delete(idx.documents, uid)
+}
+
+// Version returns the version of the dashboard indexed, and false if the dashboard isn't indexed
+func (idx *Index) Version(uid string) (int, bool) {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	doc, exist := idx.documents[uid]
+	if !exist {
+		return 0, false
+	}
+	return doc.version, true
+}
+
+// UIDs returns the uid of every dashboard indexed, sorted
+func (idx *Index) UIDs() []string {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	result := make([]string, 0, len(idx.documents))
+	for uid := range idx.documents {
+		result = append(result, uid)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Search returns the entries matching the query, sorted by dashboard and then by path
+func (idx *Index) Search(query Query) ([]*Entry, error) {
+	if len(query.Pattern) == 0 {
+		return nil, fmt.Errorf("the pattern is empty")
+	}
+	match, err := query.matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	var result []*Entry
+	for _, doc := range idx.documents {
+		for _, entry := range doc.entries {
+			if query.acceptKind(entry.Kind) && match(entry.Value) {
+				result = append(result, entry)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DashboardUID != result[j].DashboardUID {
+			return result[i].DashboardUID < result[j].DashboardUID
+		}
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+// extract returns the texts of the dashboard to index
+func extract(uid string, model types.DashboardModel) []*Entry {
+	title := model.Title()
+	var entries []*Entry
+	add := func(kind EntryKind, panelID int64, path string, value interface{}) {
+		if text, isString := value.(string); isString && len(text) > 0 {
+			entries = append(entries, &Entry{
+				DashboardUID:   uid,
+				DashboardTitle: title,
+				PanelID:        panelID,
+				Kind:           kind,
+				Path:           path,
+				Value:          text,
+			})
+		}
+	}
+	addQueries := func(panelID int64, path string, object map[string]interface{}) {
+		for _, field := range queryFields {
+			add(QueryEntry, panelID, dashboard.ChildPath(path, field), object[field])
+		}
+	}
+
+	add(DashboardTitleEntry, 0, "$.title", title)
+	_ = dashboard.WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		panelID := dashboard.PanelID(panel)
+		add(PanelTitleEntry, panelID, dashboard.ChildPath(path, "title"), panel["title"])
+		for i, target := range dashboard.Targets(panel) {
+			addQueries(panelID, dashboard.IndexPath(dashboard.ChildPath(path, "targets"), i), target)
+		}
+		return nil
+	})
+	for i, variable := range dashboard.Variables(model) {
+		path := dashboard.IndexPath("$.templating.list", i)
+		add(VariableEntry, 0, dashboard.ChildPath(path, "name"), variable["name"])
+		query := variable["query"]
+		if object, isObject := query.(map[string]interface{}); isObject {
+			addQueries(0, dashboard.ChildPath(path, "query"), object)
+		} else {
+			add(QueryEntry, 0, dashboard.ChildPath(path, "query"), query)
+		}
+	}
+	for i, annotation := range dashboard.Annotations(model) {
+		path := dashboard.IndexPath("$.annotations.list", i)
+		addQueries(0, path, annotation)
+		if target, isObject := annotation["target"].(map[string]interface{}); isObject {
+			addQueries(0, dashboard.ChildPath(path, "target"), target)
+		}
+	}
+	for _, field := range dashboard.DatasourceFields(model) {
+		add(DatasourceEntry, field.PanelID, field.Path, field.Ref.Key())
+	}
+	return entries
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDashboard = `{
+  "uid": "api",
+  "title": "API",
+  "version": 3,
+  "panels": [
+    {"id": 1, "title": "Requests on $cluster", "datasource": {"type": "prometheus", "uid": "prom-uid"}, "targets": [
+      {"refId": "A", "expr": "sum(rate(http_requests_total{cluster=\"$cluster\"}[5m]))"}
+    ]},
+    {"id": 2, "type": "row", "title": "Details", "collapsed": true, "panels": [
+      {"id": 3, "title": "Errors", "targets": [{"refId": "A", "expr": "sum(rate(http_errors_total{cluster=\"${cluster:regex}\"}[5m]))"}]}
+    ]}
+  ],
+  "templating": {"list": [
+    {"name": "cluster", "type": "query", "query": {"query": "label_values(up, cluster)", "refId": "A"}}
+  ]}
+}`
+
+func newTestModel(t *testing.T, content string) types.DashboardModel {
+	model := make(types.DashboardModel)
+	assert.Nil(t, json.Unmarshal([]byte(content), &model))
+	return model
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := New()
+	assert.Nil(t, idx.Add(newTestModel(t, testDashboard)))
+	version, indexed := idx.Version("api")
+	assert.True(t, indexed)
+	assert.Equal(t, 3, version)
+
+	testSuites := []struct {
+		title string
+		query Query
+		paths []string
+	}{
+		{
+			title: "substring",
+			query: Query{Pattern: "http_requests_total"},
+			paths: []string{"$.panels[0].targets[0].expr"},
+		},
+		{
+			title: "ignore case restricted to the panel titles",
+			query: Query{Pattern: "errors", IgnoreCase: true, Kinds: []EntryKind{PanelTitleEntry}},
+			paths: []string{"$.panels[1].panels[0].title"},
+		},
+		{
+			title: "regexp",
+			query: Query{Pattern: `^label_values\(up`, Regexp: true},
+			paths: []string{"$.templating.list[0].query.query"},
+		},
+		{
+			title: "datasource",
+			query: Query{Pattern: "prom-uid", Kinds: []EntryKind{DatasourceEntry}},
+			paths: []string{"$.panels[0].datasource"},
+		},
+		{
+			title: "variable usage",
+			query: UsesVariable("cluster"),
+			paths: []string{"$.panels[0].targets[0].expr", "$.panels[0].title", "$.panels[1].panels[0].targets[0].expr"},
+		},
+	}
+	for _, test := range testSuites {
+		entries, err := idx.Search(test.query)
+		assert.Nil(t, err, test.title)
+		var paths []string
+		for _, entry := range entries {
+			assert.Equal(t, "api", entry.DashboardUID, test.title)
+			paths = append(paths, entry.Path)
+		}
+		assert.Equal(t, test.paths, paths, test.title)
+	}
+
+	entries, _ := idx.Search(Query{Pattern: "http_errors_total"})
+	assert.Equal(t, int64(3), entries[0].PanelID)
+
+	_, err := idx.Search(Query{Pattern: "(", Regexp: true})
+	assert.NotNil(t, err)
+
+	idx.Remove("api")
+	assert.Empty(t, idx.UIDs())
+}
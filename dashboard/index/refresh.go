@@ -0,0 +1,103 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// RefreshReport lists the dashboards whose content changed in the index during a refresh
+type RefreshReport struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+	// Unchanged is the number of dashboards already indexed in their latest version
+	Unchanged int `json:"unchanged"`
+	// Failed contains the dashboards that couldn't be refreshed. They keep the content indexed previously, if any.
+	Failed []*RefreshFailure `json:"failed,omitempty"`
+}
+
+// RefreshFailure describes a dashboard that couldn't be refreshed
+type RefreshFailure struct {
+	UID   string `json:"uid"`
+	Error string `json:"error"`
+}
+
+// Refresh synchronizes the index with the dashboards matching the query.
+// Only the dashboards not indexed yet or having a new version are fetched, the latest version being
+// read from the history of the dashboard. The dashboards indexed that don't match the query anymore are removed,
+// so the same query must be used every time the index is refreshed.
+// An error is returned only if the search fails, the errors related to a dashboard are set in the report.
+func (idx *Index) Refresh(client api.ClientInterface, query api.QueryParameterSearch) (*RefreshReport, error) {
+	query.SearchType = types.SearchDashboardType
+	hits, err := client.Search().QueryAll(query)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RefreshReport{}
+	found := make(map[string]bool, len(hits))
+	for _, hit := range hits {
+		found[hit.UID] = true
+		indexed, unchanged, err := idx.refreshDashboard(client, hit.UID)
+		switch {
+		case err != nil:
+			report.Failed = append(report.Failed, &RefreshFailure{UID: hit.UID, Error: err.Error()})
+		case unchanged:
+			report.Unchanged++
+		case indexed:
+			report.Updated = append(report.Updated, hit.UID)
+		default:
+			report.Added = append(report.Added, hit.UID)
+		}
+	}
+	for _, uid := range idx.UIDs() {
+		if !found[uid] {
+			idx.Remove(uid)
+			report.Removed = append(report.Removed, uid)
+		}
+	}
+	return report, nil
+}
+
+// refreshDashboard indexes the dashboard if it's not indexed yet or if it has a new version.
+// It returns whether the dashboard was already indexed and whether it was indexed in its latest version.
+func (idx *Index) refreshDashboard(client api.ClientInterface, uid string) (bool, bool, error) {
+	indexedVersion, indexed := idx.Version(uid)
+	if indexed {
+		versions, err := client.Dashboards().GetVersionsByUID(uid, api.QueryParameterDashboardVersions{Limit: 1})
+		if err != nil {
+			return indexed, false, err
+		}
+		if latestVersion(versions.Versions) == indexedVersion {
+			return indexed, true, nil
+		}
+	}
+	result, err := client.Dashboards().GetByUID(uid)
+	if err != nil {
+		return indexed, false, err
+	}
+	return indexed, false, idx.Add(result.Dashboard)
+}
+
+func latestVersion(versions []*types.DashboardVersion) int {
+	latest := 0
+	for _, version := range versions {
+		if version.Version > latest {
+			latest = version.Version
+		}
+	}
+	return latest
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex_Refresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/search":
+			_, _ = w.Write([]byte(`[{"uid": "api", "type": "dash-db"}, {"uid": "node", "type": "dash-db"}, {"uid": "db", "type": "dash-db"}]`))
+		case "/api/dashboards/uid/api/versions":
+			_, _ = w.Write([]byte(`[{"version": 3}]`))
+		case "/api/dashboards/uid/node/versions":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "Access denied"}`))
+		case "/api/dashboards/uid/db":
+			_, _ = w.Write([]byte(`{"meta": {}, "dashboard": {"uid": "db", "title": "Database", "version": 2}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+
+	idx := New()
+	assert.Nil(t, idx.Add(newTestModel(t, testDashboard)))
+	assert.Nil(t, idx.Add(newTestModel(t, `{"uid": "node", "title": "Node", "version": 1}`)))
+	assert.Nil(t, idx.Add(newTestModel(t, `{"uid": "old", "title": "Old", "version": 1}`)))
+
+	report, err := idx.Refresh(api.NewWithClient(rest), api.QueryParameterSearch{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, []string{"db"}, report.Added)
+	assert.Empty(t, report.Updated)
+	assert.Equal(t, []string{"old"}, report.Removed)
+	// the dashboard that failed doesn't stop the refresh and keeps its previous content
+	if assert.Len(t, report.Failed, 1) {
+		assert.Equal(t, "node", report.Failed[0].UID)
+		assert.Contains(t, report.Failed[0].Error, "Access denied")
+	}
+	version, indexed := idx.Version("node")
+	assert.True(t, indexed)
+	assert.Equal(t, 1, version)
+}
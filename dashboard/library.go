@@ -0,0 +1,73 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// LibraryPanelUIDs returns the uid of every library panel used by the dashboard, without duplicate
+func LibraryPanelUIDs(model types.DashboardModel) []string {
+	var result []string
+	seen := make(map[string]bool)
+	_ = WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		if uid := libraryPanelUID(panel); len(uid) > 0 && !seen[uid] {
+			seen[uid] = true
+			result = append(result, uid)
+		}
+		return nil
+	})
+	return result
+}
+
+// InlineLibraryPanels replaces every library panel by a copy of its model, so the dashboard doesn't depend
+// on the library panels anymore. The id and the position of the panels are kept.
+// It returns the uid of the library panels not found in elements, these panels are left untouched.
+func InlineLibraryPanels(model types.DashboardModel, elements map[string]*types.LibraryElement) ([]string, error) {
+	var missing []string
+	err := WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		uid := libraryPanelUID(panel)
+		if len(uid) == 0 {
+			return nil
+		}
+		element, exist := elements[uid]
+		if !exist || element == nil {
+			missing = append(missing, uid)
+			return nil
+		}
+		inline := make(map[string]interface{})
+		if err := convert(element.Model, &inline); err != nil {
+			return err
+		}
+		delete(inline, "libraryPanel")
+		for _, key := range []string{"id", "gridPos"} {
+			if value, exist := panel[key]; exist {
+				inline[key] = value
+			}
+		}
+		for key := range panel {
+			delete(panel, key)
+		}
+		for key, value := range inline {
+			panel[key] = value
+		}
+		return nil
+	})
+	return missing, err
+}
+
+func libraryPanelUID(panel map[string]interface{}) string {
+	libraryPanel, _ := panel["libraryPanel"].(map[string]interface{})
+	return getString(libraryPanel, "uid")
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineLibraryPanels(t *testing.T) {
+	model := newTestDashboard(t, `{"panels": [
+    {"id": 1, "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8}, "libraryPanel": {"uid": "lib-a", "name": "cpu"}},
+    {"id": 2, "type": "row", "collapsed": true, "panels": [
+      {"id": 3, "gridPos": {"x": 0, "y": 9, "w": 12, "h": 8}, "libraryPanel": {"uid": "lib-a", "name": "cpu"}},
+      {"id": 4, "gridPos": {"x": 12, "y": 9, "w": 12, "h": 8}, "libraryPanel": {"uid": "lib-b", "name": "memory"}}
+    ]}
+  ]}`)
+	assert.Equal(t, []string{"lib-a", "lib-b"}, LibraryPanelUIDs(model))
+
+	missing, err := InlineLibraryPanels(model, map[string]*types.LibraryElement{
+		"lib-a": {UID: "lib-a", Model: map[string]interface{}{
+			"id": 42, "type": "timeseries", "title": "cpu", "gridPos": map[string]interface{}{"x": 6, "y": 6, "w": 6, "h": 6},
+		}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"lib-b"}, missing)
+	panels := Panels(model)
+	assert.Equal(t, map[string]interface{}{
+		"id": float64(1), "type": "timeseries", "title": "cpu", "gridPos": map[string]interface{}{"x": float64(0), "y": float64(0), "w": float64(12), "h": float64(8)},
+	}, panels[0])
+	assert.Equal(t, float64(3), panels[2]["id"])
+	assert.Nil(t, panels[2]["libraryPanel"])
+	assert.NotNil(t, panels[3]["libraryPanel"])
+	assert.Equal(t, []string{"lib-b"}, LibraryPanelUIDs(model))
+}
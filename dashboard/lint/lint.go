@@ -0,0 +1,165 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks a dashboard against a set of rules and reports the problems found,
+// so a bad dashboard can be rejected before it reaches Grafana.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding is a problem found by a rule
+type Finding struct {
+	// Rule is the name of the rule reporting the problem. It's set by the linter.
+	Rule string `json:"rule"`
+	// Severity is the one of the rule when it's not set by the rule itself
+	Severity Severity `json:"severity"`
+	// Path is the JSON path of the element having the problem, like $.panels[2].gridPos
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Context contains what the rules need to know besides the dashboard itself
+type Context struct {
+	// Datasources are the datasources existing in Grafana.
+	// When it's nil, the rules checking that a datasource exists are skipped.
+	Datasources []*types.DataSource
+}
+
+// NewContext returns a context filled with the datasources existing in Grafana
+func NewContext(client api.ClientInterface) (*Context, error) {
+	datasources, err := client.DataSources().Get()
+	if err != nil {
+		return nil, err
+	}
+	return &Context{Datasources: datasources}, nil
+}
+
+// CheckFunc returns the problems found in the dashboard. The dashboard must not be modified.
+type CheckFunc func(model types.DashboardModel, context *Context) []*Finding
+
+type Rule struct {
+	// Name identifies the rule, like duplicate-panel-id
+	Name        string
+	Description string
+	// Severity is the default severity of the findings
+	Severity Severity
+	Check    CheckFunc
+}
+
+type Linter struct {
+	rules []*Rule
+}
+
+// New returns a linter running the default rules. More rules can be added with Register.
+func New() *Linter {
+	return &Linter{rules: DefaultRules()}
+}
+
+// NewWithRules returns a linter running only the given rules
+func NewWithRules(rules ...*Rule) (*Linter, error) {
+	linter := &Linter{}
+	for _, rule := range rules {
+		if err := linter.Register(rule); err != nil {
+			return nil, err
+		}
+	}
+	return linter, nil
+}
+
+// Register adds a rule to the linter. The name of the rule must be unique.
+func (l *Linter) Register(rule *Rule) error {
+	if rule == nil || len(rule.Name) == 0 || rule.Check == nil {
+		return fmt.Errorf("a rule must have a name and a check function")
+	}
+	if l.Rule(rule.Name) != nil {
+		return fmt.Errorf("a rule named '%s' is already registered", rule.Name)
+	}
+	l.rules = append(l.rules, rule)
+	return nil
+}
+
+// Disable removes the rules with the given names. Unknown names are ignored.
+func (l *Linter) Disable(names ...string) {
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		disabled[name] = true
+	}
+	rules := make([]*Rule, 0, len(l.rules))
+	for _, rule := range l.rules {
+		if !disabled[rule.Name] {
+			rules = append(rules, rule)
+		}
+	}
+	l.rules = rules
+}
+
+// Rule returns the rule registered with this name or nil if it doesn't exist
+func (l *Linter) Rule(name string) *Rule {
+	for _, rule := range l.rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Rules returns the rules run by the linter
+func (l *Linter) Rules() []*Rule {
+	return append([]*Rule{}, l.rules...)
+}
+
+// Lint runs every rule on the dashboard and returns the findings sorted by path.
+// The context can be nil.
+func (l *Linter) Lint(model types.DashboardModel, context *Context) []*Finding {
+	if context == nil {
+		context = &Context{}
+	}
+	var findings []*Finding
+	for _, rule := range l.rules {
+		for _, finding := range rule.Check(model, context) {
+			finding.Rule = rule.Name
+			if len(finding.Severity) == 0 {
+				finding.Severity = rule.Severity
+			}
+			findings = append(findings, finding)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Path < findings[j].Path
+	})
+	return findings
+}
+
+// HasErrors returns true if at least one finding has the severity error
+func HasErrors(findings []*Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const lintTestDashboard = `{
+  "uid": "abcd",
+  "title": "my dashboard",
+  "panels": [
+    {"id": 1, "type": "timeseries", "title": "cpu", "description": "cpu usage", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+     "datasource": {"type": "prometheus", "uid": "${ds}"},
+     "targets": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "${ds}"}, "expr": "rate(cpu{job=\"$job\"}[5m])"}]},
+    {"id": 2, "type": "timeseries", "title": "", "gridPos": {"x": 6, "y": 4, "w": 12, "h": 8},
+     "datasource": {"type": "prometheus", "uid": "prom-uid"},
+     "targets": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "prom-uid"}}]},
+    {"id": 3, "type": "row", "title": "details", "collapsed": true, "gridPos": {"x": 0, "y": 12, "w": 24, "h": 1}, "panels": [
+      {"id": 2, "type": "stat", "title": "memory", "description": "memory", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+       "datasource": "Loki logs", "repeat": "instance"}
+    ]}
+  ],
+  "templating": {"list": [
+    {"name": "ds", "type": "datasource", "query": "prometheus"},
+    {"name": "job", "type": "query", "datasource": {"type": "prometheus", "uid": "${ds}"}, "query": "label_values(up, job)"},
+    {"name": "instance", "type": "query", "datasource": {"type": "prometheus", "uid": "${ds}"}, "query": "label_values(up{job=\"$job\"}, instance)"},
+    {"name": "env", "type": "constant", "query": "production"}
+  ]}
+}`
+
+func newTestDashboard(t *testing.T) types.DashboardModel {
+	model := make(types.DashboardModel)
+	assert.Nil(t, json.Unmarshal([]byte(lintTestDashboard), &model))
+	return model
+}
+
+func TestDefaultRules(t *testing.T) {
+	context := &Context{Datasources: []*types.DataSource{
+		{ID: 1, UID: "prom-uid", Name: "Prometheus main", Type: "prometheus"},
+	}}
+	testSuites := []struct {
+		rule     string
+		expected []*Finding
+	}{
+		{
+			rule: DuplicatePanelIDRule,
+			expected: []*Finding{
+				{Rule: DuplicatePanelIDRule, Severity: SeverityError, Path: "$.panels[2].panels[0].id", Message: "the id 2 is already used by the panel $.panels[1]"},
+			},
+		},
+		{
+			rule: OverlappingGridPosRule,
+			expected: []*Finding{
+				{Rule: OverlappingGridPosRule, Severity: SeverityWarning, Path: "$.panels[1].gridPos", Message: "the panel overlaps the panel $.panels[0]"},
+			},
+		},
+		{
+			rule: HardcodedDatasourceRule,
+			expected: []*Finding{
+				{Rule: HardcodedDatasourceRule, Severity: SeverityWarning, Path: "$.panels[1].datasource", Message: "the datasource 'prom-uid' is hardcoded, a datasource variable should be used"},
+				{Rule: HardcodedDatasourceRule, Severity: SeverityWarning, Path: "$.panels[2].panels[0].datasource", Message: "the datasource 'Loki logs' is hardcoded, a datasource variable should be used"},
+			},
+		},
+		{
+			rule: UnknownDatasourceRule,
+			expected: []*Finding{
+				{Rule: UnknownDatasourceRule, Severity: SeverityError, Path: "$.panels[2].panels[0].datasource", Message: "the datasource 'Loki logs' doesn't exist"},
+			},
+		},
+		{
+			rule: UnusedVariableRule,
+			expected: []*Finding{
+				{Rule: UnusedVariableRule, Severity: SeverityWarning, Path: "$.templating.list[3]", Message: "the variable 'env' is not used"},
+			},
+		},
+		{
+			rule: MissingTitleRule,
+			expected: []*Finding{
+				{Rule: MissingTitleRule, Severity: SeverityWarning, Path: "$.panels[1]", Message: "the panel has no title"},
+			},
+		},
+		{
+			rule: MissingDescriptionRule,
+			expected: []*Finding{
+				{Rule: MissingDescriptionRule, Severity: SeverityInfo, Path: "$.panels[1]", Message: "the panel has no description"},
+			},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.rule, func(t *testing.T) {
+			linter := New()
+			rule := linter.Rule(test.rule)
+			assert.NotNil(t, rule)
+			onlyRule, err := NewWithRules(rule)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, onlyRule.Lint(newTestDashboard(t), context))
+		})
+	}
+}
+
+func TestLinter_Register(t *testing.T) {
+	linter := New()
+	linter.Disable(MissingDescriptionRule, MissingTitleRule)
+	assert.Nil(t, linter.Rule(MissingDescriptionRule))
+
+	err := linter.Register(&Rule{
+		Name:     "require-tag",
+		Severity: SeverityError,
+		Check: func(model types.DashboardModel, _ *Context) []*Finding {
+			if _, exist := model["tags"]; !exist {
+				return []*Finding{{Path: "$.tags", Message: "the dashboard must have tags"}}
+			}
+			return nil
+		},
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, linter.Register(&Rule{Name: DuplicatePanelIDRule, Check: checkDuplicatePanelID}))
+
+	findings := linter.Lint(newTestDashboard(t), nil)
+	assert.True(t, HasErrors(findings))
+	found := false
+	for _, finding := range findings {
+		assert.NotEqual(t, UnknownDatasourceRule, finding.Rule)
+		if finding.Rule == "require-tag" {
+			found = true
+			assert.Equal(t, SeverityError, finding.Severity)
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,298 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+const (
+	DuplicatePanelIDRule    = "duplicate-panel-id"
+	OverlappingGridPosRule  = "overlapping-grid-pos"
+	HardcodedDatasourceRule = "hardcoded-datasource"
+	UnknownDatasourceRule   = "unknown-datasource"
+	UnusedVariableRule      = "unused-variable"
+	MissingTitleRule        = "missing-title"
+	MissingDescriptionRule  = "missing-description"
+)
+
+const (
+	rowPanelType   = "row"
+	templatingPath = "$.templating.list"
+)
+
+// DefaultRules returns the rules provided by this package
+func DefaultRules() []*Rule {
+	return []*Rule{
+		{
+			Name:        DuplicatePanelIDRule,
+			Description: "Every panel must have a unique id",
+			Severity:    SeverityError,
+			Check:       checkDuplicatePanelID,
+		},
+		{
+			Name:        OverlappingGridPosRule,
+			Description: "Two panels must not be displayed at the same place",
+			Severity:    SeverityWarning,
+			Check:       checkOverlappingGridPos,
+		},
+		{
+			Name:        HardcodedDatasourceRule,
+			Description: "The datasources should be referenced with a variable to make the dashboard portable",
+			Severity:    SeverityWarning,
+			Check:       checkHardcodedDatasource,
+		},
+		{
+			Name:        UnknownDatasourceRule,
+			Description: "Every datasource referenced must exist in Grafana",
+			Severity:    SeverityError,
+			Check:       checkUnknownDatasource,
+		},
+		{
+			Name:        UnusedVariableRule,
+			Description: "Every template variable should be used",
+			Severity:    SeverityWarning,
+			Check:       checkUnusedVariable,
+		},
+		{
+			Name:        MissingTitleRule,
+			Description: "The dashboard and its panels must have a title",
+			Severity:    SeverityWarning,
+			Check:       checkMissingTitle,
+		},
+		{
+			Name:        MissingDescriptionRule,
+			Description: "The panels should have a description",
+			Severity:    SeverityInfo,
+			Check:       checkMissingDescription,
+		},
+	}
+}
+
+func checkDuplicatePanelID(model types.DashboardModel, _ *Context) []*Finding {
+	var findings []*Finding
+	paths := make(map[int64]string)
+	_ = dashboard.WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		id := dashboard.PanelID(panel)
+		if id == 0 {
+			findings = append(findings, &Finding{Path: path, Message: "the panel has no id"})
+			return nil
+		}
+		if firstPath, exist := paths[id]; exist {
+			findings = append(findings, &Finding{
+				Path:    dashboard.ChildPath(path, "id"),
+				Message: fmt.Sprintf("the id %d is already used by the panel %s", id, firstPath),
+			})
+			return nil
+		}
+		paths[id] = path
+		return nil
+	})
+	return findings
+}
+
+type gridPos struct {
+	path       string
+	x, y, w, h float64
+}
+
+func (g gridPos) overlaps(other gridPos) bool {
+	return g.x < other.x+other.w && other.x < g.x+g.w && g.y < other.y+other.h && other.y < g.y+g.h
+}
+
+func checkOverlappingGridPos(model types.DashboardModel, _ *Context) []*Finding {
+	// the panels of a collapsed row are positioned as if the row was expanded,
+	// so they are compared only with the other panels of the row.
+	groups := make(map[string][]gridPos)
+	var order []string
+	_ = dashboard.WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		position, isObject := panel["gridPos"].(map[string]interface{})
+		if !isObject {
+			return nil
+		}
+		group := "$"
+		if index := strings.LastIndex(path, ".panels["); index > 0 {
+			group = path[:index]
+		}
+		if _, exist := groups[group]; !exist {
+			order = append(order, group)
+		}
+		x, _ := position["x"].(float64)
+		y, _ := position["y"].(float64)
+		w, _ := position["w"].(float64)
+		h, _ := position["h"].(float64)
+		groups[group] = append(groups[group], gridPos{path: path, x: x, y: y, w: w, h: h})
+		return nil
+	})
+	var findings []*Finding
+	for _, group := range order {
+		positions := groups[group]
+		for i := 1; i < len(positions); i++ {
+			for j := 0; j < i; j++ {
+				if positions[i].overlaps(positions[j]) {
+					findings = append(findings, &Finding{
+						Path:    dashboard.ChildPath(positions[i].path, "gridPos"),
+						Message: fmt.Sprintf("the panel overlaps the panel %s", positions[j].path),
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func checkHardcodedDatasource(model types.DashboardModel, _ *Context) []*Finding {
+	var findings []*Finding
+	// a query using the same datasource as its panel is not reported again
+	panelRefs := make(map[string]string)
+	for _, field := range dashboard.DatasourceFields(model) {
+		if field.Kind == dashboard.PanelField {
+			panelRefs[strings.TrimSuffix(field.Path, ".datasource")] = field.Ref.Key()
+		}
+		if field.Ref.IsVariable() || field.Ref.IsBuiltIn() {
+			continue
+		}
+		if field.Kind == dashboard.TargetField {
+			panelPath := field.Path[:strings.LastIndex(field.Path, ".targets[")]
+			if panelRefs[panelPath] == field.Ref.Key() {
+				continue
+			}
+		}
+		findings = append(findings, &Finding{
+			Path:    field.Path,
+			Message: fmt.Sprintf("the datasource '%s' is hardcoded, a datasource variable should be used", field.Ref.Key()),
+		})
+	}
+	return findings
+}
+
+func checkUnknownDatasource(model types.DashboardModel, context *Context) []*Finding {
+	if context.Datasources == nil {
+		return nil
+	}
+	var findings []*Finding
+	for _, field := range dashboard.DatasourceFields(model) {
+		if field.Ref.IsVariable() || field.Ref.IsBuiltIn() {
+			continue
+		}
+		exist := false
+		for _, datasource := range context.Datasources {
+			if field.Ref.Matches(datasource) {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			findings = append(findings, &Finding{
+				Path:    field.Path,
+				Message: fmt.Sprintf("the datasource '%s' doesn't exist", field.Ref.Key()),
+			})
+		}
+	}
+	return findings
+}
+
+func checkUnusedVariable(model types.DashboardModel, _ *Context) []*Finding {
+	var findings []*Finding
+	variables := dashboard.Variables(model)
+	for i, variable := range variables {
+		name, _ := variable["name"].(string)
+		if len(name) == 0 {
+			continue
+		}
+		reference := regexp.MustCompile(dashboard.VariableReferencePattern(name))
+		used := false
+		for key, value := range model {
+			if key == "templating" {
+				continue
+			}
+			if isVariableUsed(value, name, reference) {
+				used = true
+				break
+			}
+		}
+		// a variable can be used by another variable
+		for j, other := range variables {
+			if used {
+				break
+			}
+			used = i != j && isVariableUsed(other, name, reference)
+		}
+		if !used {
+			findings = append(findings, &Finding{
+				Path:    dashboard.IndexPath(templatingPath, i),
+				Message: fmt.Sprintf("the variable '%s' is not used", name),
+			})
+		}
+	}
+	return findings
+}
+
+// isVariableUsed looks for a reference to the variable in every string of the value.
+// The panels and the rows repeated over a variable reference it by its name without $.
+func isVariableUsed(value interface{}, name string, reference *regexp.Regexp) bool {
+	switch v := value.(type) {
+	case string:
+		return reference.MatchString(v)
+	case []interface{}:
+		for _, item := range v {
+			if isVariableUsed(item, name, reference) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		if repeat, _ := v["repeat"].(string); repeat == name {
+			return true
+		}
+		for _, item := range v {
+			if isVariableUsed(item, name, reference) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func checkMissingTitle(model types.DashboardModel, _ *Context) []*Finding {
+	var findings []*Finding
+	if len(strings.TrimSpace(model.Title())) == 0 {
+		findings = append(findings, &Finding{Path: "$.title", Severity: SeverityError, Message: "the dashboard has no title"})
+	}
+	_ = dashboard.WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		if title, _ := panel["title"].(string); len(strings.TrimSpace(title)) == 0 {
+			findings = append(findings, &Finding{Path: path, Message: "the panel has no title"})
+		}
+		return nil
+	})
+	return findings
+}
+
+func checkMissingDescription(model types.DashboardModel, _ *Context) []*Finding {
+	var findings []*Finding
+	_ = dashboard.WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		if dashboard.PanelType(panel) == rowPanelType {
+			return nil
+		}
+		if description, _ := panel["description"].(string); len(strings.TrimSpace(description)) == 0 {
+			findings = append(findings, &Finding{Path: path, Message: "the panel has no description"})
+		}
+		return nil
+	})
+	return findings
+}
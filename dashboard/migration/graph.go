@@ -0,0 +1,433 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"fmt"
+	"sort"
+)
+
+// legendReducers are the legend values of the graph panel, in the order they are migrated
+var legendReducers = []string{"avg", "current", "max", "min", "total"}
+
+// migrateGraph converts a graph panel into a timeseries panel
+func migrateGraph(panel map[string]interface{}) (string, map[string]interface{}, map[string]interface{}, []string, error) {
+	xaxis := getObject(panel, "xaxis")
+	if mode := getString(xaxis, "mode"); len(mode) > 0 && mode != "time" {
+		return "", nil, nil, nil, fmt.Errorf("a graph with an x-axis in mode '%s' can't be converted into a timeseries", mode)
+	}
+	var warnings []string
+
+	yaxes := getList(panel, "yaxes")
+	var y1Axis, y2Axis map[string]interface{}
+	if len(yaxes) > 0 {
+		y1Axis, _ = yaxes[0].(map[string]interface{})
+	}
+	if len(yaxes) > 1 {
+		y2Axis, _ = yaxes[1].(map[string]interface{})
+	}
+
+	oldFieldConfig := getObject(panel, "fieldConfig")
+	defaults := make(map[string]interface{})
+	for key, value := range getObject(oldFieldConfig, "defaults") {
+		defaults[key] = value
+	}
+	for key, value := range fieldConfigFromAxis(y1Axis) {
+		defaults[key] = value
+	}
+	overrides := make([]interface{}, 0)
+	overrides = append(overrides, getList(oldFieldConfig, "overrides")...)
+
+	dashStyle := map[string]interface{}{"fill": "solid", "dash": []interface{}{numberOr(panel, "dashLength", 10), numberOr(panel, "spaceLength", 10)}}
+	if getBool(panel, "dashes") {
+		dashStyle["fill"] = "dash"
+	}
+
+	aliasColors := getObject(panel, "aliasColors")
+	for _, alias := range sortedKeys(aliasColors) {
+		color := aliasColors[alias]
+		if color == nil || color == "" {
+			continue
+		}
+		overrides = append(overrides, newOverride(alias, []interface{}{
+			property("color", map[string]interface{}{"mode": "fixed", "fixedColor": color}),
+		}))
+	}
+
+	hasFillBelowTo := false
+	for _, item := range getList(panel, "seriesOverrides") {
+		seriesOverride, _ := item.(map[string]interface{})
+		alias := getString(seriesOverride, "alias")
+		if len(alias) == 0 {
+			continue
+		}
+		var properties []interface{}
+		var dashOverride map[string]interface{}
+		for _, key := range sortedKeys(seriesOverride) {
+			value := seriesOverride[key]
+			number, _ := getNumber(seriesOverride, key)
+			switch key {
+			case "alias", "$$hashKey":
+			case "yaxis":
+				if number == 2 {
+					properties = append(properties, y2Properties(defaults, fieldConfigFromAxis(y2Axis))...)
+				}
+			case "fill":
+				properties = append(properties, property("custom.fillOpacity", number*10))
+			case "fillBelowTo":
+				hasFillBelowTo = true
+				properties = append(properties, property("custom.fillBelowTo", value))
+			case "fillGradient":
+				if number > 0 {
+					properties = append(properties,
+						property("custom.fillGradient", "opacity"),
+						property("custom.fillOpacity", number*10))
+				}
+			case "points":
+				properties = append(properties, property("custom.showPoints", visibility(value == true)))
+			case "bars":
+				if value == true {
+					properties = append(properties, property("custom.drawStyle", "bars"), property("custom.fillOpacity", float64(100)))
+				} else {
+					properties = append(properties, property("custom.drawStyle", "line"))
+				}
+			case "lines":
+				if value == true {
+					properties = append(properties, property("custom.drawStyle", "line"))
+				} else {
+					properties = append(properties, property("custom.lineWidth", float64(0)))
+				}
+			case "linewidth":
+				properties = append(properties, property("custom.lineWidth", value))
+			case "pointradius":
+				properties = append(properties, property("custom.pointSize", 2+number*2))
+			case "dashLength", "spaceLength", "dashes":
+				if dashOverride == nil {
+					dash := dashStyle["dash"].([]interface{})
+					dashOverride = map[string]interface{}{"fill": dashStyle["fill"], "dash": []interface{}{dash[0], dash[1]}}
+				}
+				switch key {
+				case "dashLength":
+					dashOverride["dash"].([]interface{})[0] = number
+				case "spaceLength":
+					dashOverride["dash"].([]interface{})[1] = number
+				case "dashes":
+					dashOverride["fill"] = "solid"
+					if value == true {
+						dashOverride["fill"] = "dash"
+					}
+				}
+			case "stack":
+				stacking := map[string]interface{}{"mode": "none", "group": "A"}
+				if value != false && value != nil {
+					stacking["mode"] = "normal"
+				}
+				if group, isString := value.(string); isString {
+					stacking["group"] = "A-" + group
+				}
+				properties = append(properties, property("custom.stacking", stacking))
+			case "color":
+				properties = append(properties, property("color", map[string]interface{}{"mode": "fixed", "fixedColor": value}))
+			case "transform":
+				switch value {
+				case "negative-Y":
+					properties = append(properties, property("custom.transform", "negative-Y"))
+				case "constant":
+					properties = append(properties, property("custom.transform", "constant"))
+				}
+			default:
+				warnings = append(warnings, fmt.Sprintf("the property '%s' of the series override '%s' is not migrated", key, alias))
+			}
+		}
+		if dashOverride != nil {
+			properties = append(properties, property("custom.lineStyle", dashOverride))
+		}
+		if len(properties) > 0 {
+			overrides = append(overrides, newOverride(alias, properties))
+		}
+	}
+
+	custom, _ := defaults["custom"].(map[string]interface{})
+	if custom == nil {
+		custom = make(map[string]interface{})
+	}
+	drawStyle := "points"
+	if getBool(panel, "bars") {
+		drawStyle = "bars"
+	} else if getBool(panel, "lines") {
+		drawStyle = "line"
+	}
+	custom["drawStyle"] = drawStyle
+	if getBool(panel, "points") {
+		custom["showPoints"] = "always"
+		if radius, isNumber := getNumber(panel, "pointradius"); isNumber {
+			custom["pointSize"] = 2 + radius*2
+		}
+	} else if drawStyle != "points" {
+		custom["showPoints"] = "never"
+	}
+	if lineWidth, exist := panel["linewidth"]; exist && lineWidth != nil {
+		custom["lineWidth"] = lineWidth
+	}
+	if dashStyle["fill"] != "solid" {
+		custom["lineStyle"] = dashStyle
+	}
+	if hasFillBelowTo {
+		// bands are hard to see without fill
+		custom["fillOpacity"] = float64(35)
+	} else if fill, isNumber := getNumber(panel, "fill"); isNumber {
+		custom["fillOpacity"] = fill * 10
+	}
+	if gradient, _ := getNumber(panel, "fillGradient"); gradient > 0 {
+		custom["gradientMode"] = "opacity"
+		custom["fillOpacity"] = gradient * 10
+	}
+	nullPointMode := getString(panel, "nullPointMode")
+	// lines are drawn across the missing points only when they were connected
+	custom["spanNulls"] = nullPointMode == "connected"
+	if getBool(panel, "steppedLine") {
+		custom["lineInterpolation"] = "stepAfter"
+	}
+	if drawStyle == "bars" {
+		// bars were always filled
+		custom["fillOpacity"] = float64(100)
+	}
+	if getBool(panel, "stack") {
+		mode := "normal"
+		if getBool(panel, "percentage") {
+			mode = "percent"
+		}
+		custom["stacking"] = map[string]interface{}{"mode": mode, "group": "A"}
+	}
+	defaults["custom"] = custom
+	if len(nullPointMode) > 0 {
+		defaults["nullValueMode"] = nullPointMode
+	}
+
+	options := map[string]interface{}{
+		"legend":  legendOptions(getObject(panel, "legend")),
+		"tooltip": tooltipOptions(getObject(panel, "tooltip")),
+	}
+
+	if thresholds := getList(panel, "thresholds"); len(thresholds) > 0 {
+		steps, mode := graphThresholds(thresholds)
+		custom["thresholdsStyle"] = map[string]interface{}{"mode": mode}
+		defaults["thresholds"] = map[string]interface{}{"mode": "absolute", "steps": steps}
+	}
+
+	if xaxis != nil && xaxis["show"] == false {
+		overrides = append(overrides, map[string]interface{}{
+			"matcher":    map[string]interface{}{"id": "byType", "options": "time"},
+			"properties": []interface{}{property("custom.axisPlacement", "hidden")},
+		})
+	}
+	if len(getList(panel, "timeRegions")) > 0 {
+		warnings = append(warnings, "the time regions are not migrated, they must be replaced by annotations")
+	}
+
+	fieldConfig := map[string]interface{}{"defaults": defaults, "overrides": overrides}
+	return "timeseries", options, fieldConfig, warnings, nil
+}
+
+// fieldConfigFromAxis converts a y-axis of the graph panel into the field config of the timeseries panel
+func fieldConfigFromAxis(axis map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if axis == nil {
+		return result
+	}
+	custom := map[string]interface{}{"axisPlacement": "hidden"}
+	if getBool(axis, "show") {
+		custom["axisPlacement"] = "auto"
+	}
+	if label := getString(axis, "label"); len(label) > 0 {
+		custom["axisLabel"] = label
+	}
+	if logBase, _ := getNumber(axis, "logBase"); logBase == 2 || logBase == 10 {
+		custom["scaleDistribution"] = map[string]interface{}{"type": "log", "log": logBase}
+	}
+	result["custom"] = custom
+	if format := getString(axis, "format"); len(format) > 0 {
+		result["unit"] = format
+	}
+	for _, key := range []string{"decimals", "min", "max"} {
+		if number, isNumber := getNumber(axis, key); isNumber {
+			result[key] = number
+		}
+	}
+	return result
+}
+
+// y2Properties returns the properties of the second y-axis that are different from the first one
+func y2Properties(y1 map[string]interface{}, y2 map[string]interface{}) []interface{} {
+	var properties []interface{}
+	for _, key := range sortedKeys(y2) {
+		if key != "custom" && y2[key] != y1[key] {
+			properties = append(properties, property(key, y2[key]))
+		}
+	}
+	y1Custom, _ := y1["custom"].(map[string]interface{})
+	y2Custom, _ := y2["custom"].(map[string]interface{})
+	for _, key := range sortedKeys(y2Custom) {
+		value := y2Custom[key]
+		if y1Value, exist := y1Custom[key]; !exist || fmt.Sprint(y1Value) != fmt.Sprint(value) {
+			properties = append(properties, property("custom."+key, value))
+		}
+	}
+	return properties
+}
+
+func legendOptions(legend map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{
+		"displayMode": "list",
+		"showLegend":  true,
+		"placement":   "bottom",
+		"calcs":       []interface{}{},
+	}
+	if legend == nil {
+		return result
+	}
+	if getBool(legend, "show") {
+		if getBool(legend, "alignAsTable") {
+			result["displayMode"] = "table"
+		}
+	} else {
+		result["showLegend"] = false
+	}
+	if getBool(legend, "rightSide") {
+		result["placement"] = "right"
+	}
+	if getBool(legend, "values") {
+		calcs := make([]interface{}, 0)
+		for _, name := range legendReducers {
+			if getBool(legend, name) {
+				reducer, _ := getReducer(name)
+				calcs = append(calcs, reducer)
+			}
+		}
+		result["calcs"] = calcs
+	}
+	if width, isNumber := getNumber(legend, "sideWidth"); isNumber && width > 0 {
+		result["width"] = width
+	}
+	return result
+}
+
+func tooltipOptions(tooltip map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{"mode": "single", "sort": "none"}
+	if tooltip == nil {
+		return result
+	}
+	if shared, exist := tooltip["shared"].(bool); exist && shared {
+		result["mode"] = "multi"
+		switch sortOrder, _ := getNumber(tooltip, "sort"); sortOrder {
+		case 1:
+			result["sort"] = "asc"
+		case 2:
+			result["sort"] = "desc"
+		}
+	}
+	return result
+}
+
+// graphThresholds converts the thresholds of the graph panel into steps.
+// It returns the steps and the way the thresholds must be displayed.
+func graphThresholds(thresholds []interface{}) ([]interface{}, string) {
+	sorted := make([]map[string]interface{}, 0, len(thresholds))
+	for _, item := range thresholds {
+		if threshold, ok := item.(map[string]interface{}); ok {
+			sorted = append(sorted, threshold)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, _ := getNumber(sorted[i], "value")
+		b, _ := getNumber(sorted[j], "value")
+		return a < b
+	})
+
+	steps := make([]interface{}, 0)
+	area, line := false, false
+	for i, threshold := range sorted {
+		var next map[string]interface{}
+		if i+1 < len(sorted) {
+			next = sorted[i+1]
+		}
+		if getBool(threshold, "fill") {
+			area = true
+		}
+		if getBool(threshold, "line") {
+			line = true
+		}
+		value, _ := getNumber(threshold, "value")
+		switch getString(threshold, "op") {
+		case "gt":
+			steps = append(steps, map[string]interface{}{"value": value, "color": graphThresholdColor(threshold)})
+		case "lt":
+			if len(steps) == 0 {
+				// the base step
+				steps = append(steps, map[string]interface{}{"value": nil, "color": graphThresholdColor(threshold)})
+			}
+			nextValue, _ := getNumber(next, "value")
+			switch {
+			case next != nil && getString(next, "op") == "gt" && nextValue > value:
+				// there is a gap between this threshold and the next one
+				steps = append(steps, map[string]interface{}{"value": value, "color": "transparent"})
+			case next != nil && getString(next, "op") == "lt":
+				steps = append(steps, map[string]interface{}{"value": value, "color": graphThresholdColor(next)})
+			default:
+				steps = append(steps, map[string]interface{}{"value": value, "color": "transparent"})
+			}
+		}
+	}
+	if len(steps) > 0 && steps[0].(map[string]interface{})["value"] != nil {
+		steps = append([]interface{}{map[string]interface{}{"value": nil, "color": "transparent"}}, steps...)
+	}
+
+	mode := "line"
+	if area {
+		mode = "area"
+	}
+	if line && area {
+		mode = "line+area"
+	}
+	return steps, mode
+}
+
+func graphThresholdColor(threshold map[string]interface{}) interface{} {
+	switch getString(threshold, "colorMode") {
+	case "critical":
+		return "red"
+	case "warning":
+		return "orange"
+	case "custom":
+		if color := getString(threshold, "fillColor"); len(color) > 0 {
+			return color
+		}
+		return threshold["lineColor"]
+	}
+	return "red"
+}
+
+func visibility(show bool) string {
+	if show {
+		return "always"
+	}
+	return "never"
+}
+
+func numberOr(object map[string]interface{}, key string, defaultValue float64) float64 {
+	if number, isNumber := getNumber(object, key); isNumber {
+		return number
+	}
+	return defaultValue
+}
@@ -0,0 +1,319 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migration converts the panels using a deprecated type (graph, singlestat, table-old)
+// into their modern equivalent (timeseries, stat or gauge, table) the same way Grafana does it
+// when such a panel is opened in a recent version.
+package migration
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard"
+)
+
+// panelMigration builds the options and the fieldConfig of the new panel from the deprecated one.
+// It returns the new type of the panel and the settings that couldn't be migrated.
+type panelMigration func(panel map[string]interface{}) (newType string, options map[string]interface{}, fieldConfig map[string]interface{}, warnings []string, err error)
+
+var panelMigrations = map[string]panelMigration{
+	"graph":                    migrateGraph,
+	"singlestat":               migrateSinglestat,
+	"grafana-singlestat-panel": migrateSinglestat,
+	"table-old":                migrateTable,
+}
+
+// keptProperties are the properties of a panel that don't depend on its type.
+// All other properties are removed when a panel is migrated, like Grafana does when the type of a panel is changed.
+var keptProperties = map[string]bool{
+	"id":               true,
+	"gridPos":          true,
+	"title":            true,
+	"description":      true,
+	"datasource":       true,
+	"targets":          true,
+	"transformations":  true,
+	"links":            true,
+	"repeat":           true,
+	"repeatDirection":  true,
+	"maxPerRow":        true,
+	"timeFrom":         true,
+	"timeShift":        true,
+	"hideTimeOverride": true,
+	"interval":         true,
+	"maxDataPoints":    true,
+	"cacheTimeout":     true,
+	"queryCachingTTL":  true,
+	"transparent":      true,
+	"libraryPanel":     true,
+	"alert":            true,
+}
+
+// PanelChange describes the migration of a panel
+type PanelChange struct {
+	// Path is the JSON path of the panel in the dashboard
+	Path     string   `json:"path"`
+	PanelID  int64    `json:"panelId"`
+	Title    string   `json:"title"`
+	FromType string   `json:"fromType"`
+	ToType   string   `json:"toType,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	// Error is set when the panel couldn't be migrated. In this case the panel is left untouched.
+	Error string `json:"error,omitempty"`
+}
+
+// IsDeprecated returns true if the panel type is one that can be migrated by this package
+func IsDeprecated(panelType string) bool {
+	_, exist := panelMigrations[panelType]
+	return exist
+}
+
+// MigratePanel converts the panel in place if its type is deprecated.
+// It returns nil if the type of the panel is not deprecated.
+// When the panel can't be migrated, it's not modified and the reason is set in the field Error of the change returned.
+func MigratePanel(panel map[string]interface{}) *PanelChange {
+	fromType := dashboard.PanelType(panel)
+	migrate, exist := panelMigrations[fromType]
+	if !exist {
+		return nil
+	}
+	change := &PanelChange{
+		PanelID:  dashboard.PanelID(panel),
+		Title:    getString(panel, "title"),
+		FromType: fromType,
+	}
+	newType, options, fieldConfig, warnings, err := migrate(panel)
+	if err != nil {
+		change.Error = err.Error()
+		return change
+	}
+	for key := range panel {
+		if !keptProperties[key] {
+			delete(panel, key)
+		}
+	}
+	panel["type"] = newType
+	panel["options"] = options
+	panel["fieldConfig"] = fieldConfig
+	change.ToType = newType
+	change.Warnings = warnings
+	return change
+}
+
+// MigrateDashboard converts in place every panel of the dashboard using a deprecated type,
+// including the panels nested in a collapsed row.
+// The library panels are not migrated since their model is stored outside of the dashboard.
+func MigrateDashboard(model types.DashboardModel) []*PanelChange {
+	var changes []*PanelChange
+	_ = dashboard.WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		if _, isLibraryPanel := panel["libraryPanel"]; isLibraryPanel {
+			return nil
+		}
+		if change := MigratePanel(panel); change != nil {
+			change.Path = path
+			changes = append(changes, change)
+		}
+		return nil
+	})
+	return changes
+}
+
+// reducers maps the name of the old aggregations to the id of the reducers used by the new panels
+var reducers = map[string]string{
+	"avg":     "mean",
+	"current": "lastNotNull",
+	"total":   "sum",
+}
+
+// validReducers are the reducers available in the new panels
+var validReducers = map[string]bool{
+	"sum": true, "max": true, "min": true, "logmin": true, "mean": true, "last": true, "first": true, "count": true,
+	"range": true, "diff": true, "delta": true, "step": true, "firstNotNull": true, "lastNotNull": true, "changeCount": true,
+	"distinctCount": true, "allIsZero": true, "allIsNull": true, "diffperc": true, "variance": true, "stdDev": true,
+}
+
+func getReducer(name string) (string, bool) {
+	if reducer, exist := reducers[name]; exist {
+		return reducer, true
+	}
+	return name, validReducers[name]
+}
+
+// overrideMatcher returns the matcher of a field override. A name surrounded by slashes is a regexp.
+func overrideMatcher(name string) map[string]interface{} {
+	id := "byName"
+	if len(name) > 1 && strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/") {
+		id = "byRegexp"
+	}
+	return map[string]interface{}{"id": id, "options": name}
+}
+
+func newOverride(name string, properties []interface{}) map[string]interface{} {
+	return map[string]interface{}{"matcher": overrideMatcher(name), "properties": properties}
+}
+
+func property(id string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"id": id, "value": value}
+}
+
+// thresholdColor returns the color of the step active for the value
+func thresholdColor(value float64, steps []interface{}) interface{} {
+	var color interface{}
+	for _, step := range steps {
+		s, _ := step.(map[string]interface{})
+		stepValue, isNumber := getNumber(s, "value")
+		if !isNumber || value >= stepValue {
+			color = s["color"]
+		}
+	}
+	return color
+}
+
+// convertValueMappings converts the value and range mappings of the old singlestat and table into the new format
+func convertValueMappings(old map[string]interface{}, steps []interface{}) []interface{} {
+	mappingType, _ := getNumber(old, "mappingType")
+	valueMaps := getList(old, "valueMaps")
+	rangeMaps := getList(old, "rangeMaps")
+	if mappingType == 0 {
+		if len(valueMaps) > 0 {
+			mappingType = 1
+		} else if len(rangeMaps) > 0 {
+			mappingType = 2
+		}
+	}
+
+	result := make([]interface{}, 0)
+	valueOptions := make(map[string]interface{})
+	resultOf := func(mapping map[string]interface{}, value string) map[string]interface{} {
+		mappingResult := map[string]interface{}{"text": mapping["text"]}
+		if number, err := strconv.ParseFloat(value, 64); err == nil && steps != nil {
+			if color := thresholdColor(number, steps); color != nil {
+				mappingResult["color"] = color
+			}
+		}
+		return mappingResult
+	}
+	switch mappingType {
+	case 1:
+		for _, item := range valueMaps {
+			mapping, _ := item.(map[string]interface{})
+			if mapping == nil || mapping["value"] == nil {
+				continue
+			}
+			value := toString(mapping["value"])
+			if value == "null" {
+				result = append(result, map[string]interface{}{
+					"type":    "special",
+					"options": map[string]interface{}{"match": "null", "result": resultOf(mapping, value)},
+				})
+				continue
+			}
+			valueOptions[value] = resultOf(mapping, value)
+		}
+	case 2:
+		for _, item := range rangeMaps {
+			mapping, _ := item.(map[string]interface{})
+			if mapping == nil {
+				continue
+			}
+			from, _ := getNumber(mapping, "from")
+			to, _ := getNumber(mapping, "to")
+			result = append(result, map[string]interface{}{
+				"type":    "range",
+				"options": map[string]interface{}{"from": from, "to": to, "result": resultOf(mapping, toString(mapping["from"]))},
+			})
+		}
+	}
+	if len(valueOptions) > 0 {
+		result = append([]interface{}{map[string]interface{}{"type": "value", "options": valueOptions}}, result...)
+	}
+	return result
+}
+
+// generateThresholds builds the steps of the thresholds from the old list of levels and colors.
+// There is one more color than levels, the first color being the base one.
+func generateThresholds(levels []string, colors []interface{}) []interface{} {
+	steps := make([]interface{}, 0, len(colors))
+	for i, color := range colors {
+		if i == 0 {
+			steps = append(steps, map[string]interface{}{"color": color, "value": nil})
+			continue
+		}
+		if i-1 >= len(levels) {
+			break
+		}
+		level, err := strconv.ParseFloat(strings.TrimSpace(levels[i-1]), 64)
+		if err != nil {
+			continue
+		}
+		steps = append(steps, map[string]interface{}{"color": color, "value": level})
+	}
+	return steps
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func getString(object map[string]interface{}, key string) string {
+	value, _ := object[key].(string)
+	return value
+}
+
+func getBool(object map[string]interface{}, key string) bool {
+	value, _ := object[key].(bool)
+	return value
+}
+
+func getObject(object map[string]interface{}, key string) map[string]interface{} {
+	value, _ := object[key].(map[string]interface{})
+	return value
+}
+
+func getList(object map[string]interface{}, key string) []interface{} {
+	value, _ := object[key].([]interface{})
+	return value
+}
+
+// getNumber returns the value as a number. A string containing a number is accepted since the old panels
+// store some numbers as strings (like the min and the max of an axis).
+func getNumber(object map[string]interface{}, key string) (float64, bool) {
+	switch v := object[key].(type) {
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPanel(t *testing.T, content string) map[string]interface{} {
+	panel := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal([]byte(content), &panel))
+	return panel
+}
+
+func TestMigratePanel(t *testing.T) {
+	testSuites := []struct {
+		title          string
+		panel          string
+		expectedType   string
+		expectedError  bool
+		expectedResult func(t *testing.T, panel map[string]interface{})
+	}{
+		{
+			title:        "panel not deprecated",
+			panel:        `{"id": 1, "type": "timeseries"}`,
+			expectedType: "",
+		},
+		{
+			title: "graph to timeseries",
+			panel: `{"id": 1, "type": "graph", "title": "cpu", "targets": [{"refId": "A"}], "lines": true, "linewidth": 2,
+				"fill": 3, "points": false, "stack": true, "legend": {"show": true},
+				"aliasColors": {"user": "red"}, "yaxes": [{"format": "percent", "min": "0", "show": true}, {"show": true}]}`,
+			expectedType: "timeseries",
+			expectedResult: func(t *testing.T, panel map[string]interface{}) {
+				assert.Equal(t, "cpu", panel["title"])
+				assert.NotNil(t, panel["targets"])
+				assert.Nil(t, panel["aliasColors"])
+				assert.Nil(t, panel["yaxes"])
+				defaults := getObject(getObject(panel, "fieldConfig"), "defaults")
+				assert.Equal(t, "percent", defaults["unit"])
+				assert.Equal(t, float64(0), defaults["min"])
+				custom := getObject(defaults, "custom")
+				assert.Equal(t, float64(2), custom["lineWidth"])
+				assert.Equal(t, float64(30), custom["fillOpacity"])
+				overrides := getList(getObject(panel, "fieldConfig"), "overrides")
+				assert.Len(t, overrides, 1)
+			},
+		},
+		{
+			title:        "graph with null points",
+			panel:        `{"id": 1, "type": "graph", "nullPointMode": "null"}`,
+			expectedType: "timeseries",
+			expectedResult: func(t *testing.T, panel map[string]interface{}) {
+				defaults := getObject(getObject(panel, "fieldConfig"), "defaults")
+				assert.Equal(t, false, getObject(defaults, "custom")["spanNulls"])
+				assert.Equal(t, "null", defaults["nullValueMode"])
+			},
+		},
+		{
+			title:        "graph with connected null points",
+			panel:        `{"id": 1, "type": "graph", "nullPointMode": "connected"}`,
+			expectedType: "timeseries",
+			expectedResult: func(t *testing.T, panel map[string]interface{}) {
+				defaults := getObject(getObject(panel, "fieldConfig"), "defaults")
+				assert.Equal(t, true, getObject(defaults, "custom")["spanNulls"])
+				assert.Equal(t, "connected", defaults["nullValueMode"])
+			},
+		},
+		{
+			title:        "graph with null points as zero",
+			panel:        `{"id": 1, "type": "graph", "nullPointMode": "null as zero"}`,
+			expectedType: "timeseries",
+			expectedResult: func(t *testing.T, panel map[string]interface{}) {
+				defaults := getObject(getObject(panel, "fieldConfig"), "defaults")
+				assert.Equal(t, false, getObject(defaults, "custom")["spanNulls"])
+				assert.Equal(t, "null as zero", defaults["nullValueMode"])
+			},
+		},
+		{
+			title:         "graph with a series x-axis",
+			panel:         `{"id": 1, "type": "graph", "xaxis": {"mode": "series"}}`,
+			expectedError: true,
+		},
+		{
+			title: "singlestat to stat",
+			panel: `{"id": 2, "type": "singlestat", "valueName": "current", "format": "bytes", "colorValue": true,
+				"thresholds": "10,20", "colors": ["green", "orange", "red"], "sparkline": {"show": true}}`,
+			expectedType: "stat",
+			expectedResult: func(t *testing.T, panel map[string]interface{}) {
+				options := getObject(panel, "options")
+				assert.Equal(t, []interface{}{"lastNotNull"}, getObject(options, "reduceOptions")["calcs"])
+				assert.Equal(t, "value", options["colorMode"])
+				assert.Equal(t, "area", options["graphMode"])
+				defaults := getObject(getObject(panel, "fieldConfig"), "defaults")
+				assert.Equal(t, "bytes", defaults["unit"])
+				assert.Len(t, getList(getObject(defaults, "thresholds"), "steps"), 3)
+			},
+		},
+		{
+			title:        "singlestat to gauge",
+			panel:        `{"id": 3, "type": "singlestat", "gauge": {"show": true, "minValue": 0, "maxValue": 100}, "prefix": "$"}`,
+			expectedType: "gauge",
+			expectedResult: func(t *testing.T, panel map[string]interface{}) {
+				defaults := getObject(getObject(panel, "fieldConfig"), "defaults")
+				assert.Equal(t, float64(0), defaults["min"])
+				assert.Equal(t, float64(100), defaults["max"])
+			},
+		},
+		{
+			title: "table-old to table",
+			panel: `{"id": 4, "type": "table-old", "transform": "timeseries_aggregations", "columns": [{"value": "avg"}, {"value": "max"}],
+				"styles": [{"pattern": "/.*/", "unit": "short"}, {"pattern": "Time", "type": "hidden"}]}`,
+			expectedType: "table",
+			expectedResult: func(t *testing.T, panel map[string]interface{}) {
+				transformations := getList(panel, "transformations")
+				assert.Len(t, transformations, 1)
+				transformation := transformations[0].(map[string]interface{})
+				assert.Equal(t, "reduce", transformation["id"])
+				assert.Equal(t, []interface{}{"mean", "max"}, getObject(transformation, "options")["reducers"])
+				fieldConfig := getObject(panel, "fieldConfig")
+				assert.Equal(t, "short", getObject(fieldConfig, "defaults")["unit"])
+				assert.Len(t, getList(fieldConfig, "overrides"), 1)
+			},
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			panel := newTestPanel(t, test.panel)
+			change := MigratePanel(panel)
+			if len(test.expectedType) == 0 && !test.expectedError {
+				assert.Nil(t, change)
+				return
+			}
+			assert.NotNil(t, change)
+			if test.expectedError {
+				assert.NotEmpty(t, change.Error)
+				assert.Equal(t, change.FromType, panel["type"])
+				return
+			}
+			assert.Empty(t, change.Error)
+			assert.Equal(t, test.expectedType, change.ToType)
+			assert.Equal(t, test.expectedType, panel["type"])
+			if test.expectedResult != nil {
+				test.expectedResult(t, panel)
+			}
+		})
+	}
+}
+
+func TestMigrateDashboard(t *testing.T) {
+	model := make(types.DashboardModel)
+	assert.Nil(t, json.Unmarshal([]byte(`{"panels": [
+		{"id": 1, "type": "graph"},
+		{"id": 2, "type": "row", "collapsed": true, "panels": [{"id": 3, "type": "singlestat"}]},
+		{"id": 4, "type": "graph", "libraryPanel": {"uid": "lib", "name": "library"}},
+		{"id": 5, "type": "text"}
+	]}`), &model))
+
+	changes := MigrateDashboard(model)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "$.panels[0]", changes[0].Path)
+	assert.Equal(t, "timeseries", changes[0].ToType)
+	assert.Equal(t, "$.panels[1].panels[0]", changes[1].Path)
+	assert.Equal(t, int64(3), changes[1].PanelID)
+	assert.Equal(t, "stat", changes[1].ToType)
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+const defaultMessage = "migrate deprecated panels"
+
+// Options configures the bulk migration done by Run
+type Options struct {
+	// Message is the commit message used when a dashboard is saved. A default message is used when it's empty.
+	Message string
+	// DryRun allows to get the report without saving any dashboard
+	DryRun bool
+}
+
+// DashboardReport describes the migration of a dashboard
+type DashboardReport struct {
+	UID    string         `json:"uid"`
+	Title  string         `json:"title"`
+	Panels []*PanelChange `json:"panels"`
+	// Version is the version of the dashboard once saved. It's 0 when it's a dry run or when the dashboard is not saved.
+	Version int `json:"version,omitempty"`
+	// Error is set when the dashboard couldn't be migrated or saved
+	Error string `json:"error,omitempty"`
+}
+
+// Report gathers the result of the migration of every dashboard
+type Report struct {
+	// Dashboards contains only the dashboards that have at least one deprecated panel or that failed
+	Dashboards []*DashboardReport `json:"dashboards"`
+}
+
+// Run migrates the deprecated panels of every dashboard matching the query.
+// Each dashboard is saved with DashboardInterface.Modify, so a concurrent modification is never overwritten.
+// An error is returned only if the search fails, the errors related to a dashboard are set in its report.
+func Run(client api.ClientInterface, query api.QueryParameterSearch, options Options) (*Report, error) {
+	message := options.Message
+	if len(message) == 0 {
+		message = defaultMessage
+	}
+	query.SearchType = types.SearchDashboardType
+	hits, err := client.Search().QueryAll(query)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, hit := range hits {
+		dashboardReport := &DashboardReport{UID: hit.UID, Title: hit.Title}
+		saved, err := client.Dashboards().Modify(hit.UID, message, func(model types.DashboardModel) error {
+			dashboardReport.Panels = MigrateDashboard(model)
+			if options.DryRun || !hasMigratedPanel(dashboardReport.Panels) {
+				return api.ErrNotModified
+			}
+			return nil
+		})
+		if err != nil {
+			dashboardReport.Error = err.Error()
+		} else if saved != nil {
+			dashboardReport.Version = saved.Version
+		}
+		if len(dashboardReport.Panels) > 0 || len(dashboardReport.Error) > 0 {
+			report.Dashboards = append(report.Dashboards, dashboardReport)
+		}
+	}
+	return report, nil
+}
+
+func hasMigratedPanel(changes []*PanelChange) bool {
+	for _, change := range changes {
+		if len(change.ToType) > 0 {
+			return true
+		}
+	}
+	return false
+}
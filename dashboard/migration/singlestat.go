@@ -0,0 +1,105 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+)
+
+// migrateSinglestat converts a singlestat panel into a stat panel, or into a gauge panel when the gauge is displayed
+func migrateSinglestat(panel map[string]interface{}) (string, map[string]interface{}, map[string]interface{}, []string, error) {
+	var warnings []string
+	valueName := getString(panel, "valueName")
+	calc, exist := getReducer(valueName)
+	if !exist {
+		calc = "mean"
+		if len(valueName) > 0 && valueName != "name" {
+			warnings = append(warnings, fmt.Sprintf("the value '%s' is not supported, the mean is used instead", valueName))
+		}
+	}
+	reduceOptions := map[string]interface{}{"calcs": []interface{}{calc}, "fields": "", "values": false}
+	if column := getString(panel, "tableColumn"); len(column) > 0 {
+		reduceOptions["fields"] = "/^" + column + "$/"
+	}
+	options := map[string]interface{}{
+		"reduceOptions": reduceOptions,
+		"orientation":   "horizontal",
+	}
+
+	defaults := make(map[string]interface{})
+	if format := getString(panel, "format"); len(format) > 0 {
+		defaults["unit"] = format
+	}
+	if nullPointMode := getString(panel, "nullPointMode"); len(nullPointMode) > 0 {
+		defaults["nullValueMode"] = nullPointMode
+	}
+	if nullText := getString(panel, "nullText"); len(nullText) > 0 {
+		defaults["noValue"] = nullText
+	}
+	if decimals, isNumber := getNumber(panel, "decimals"); isNumber {
+		defaults["decimals"] = decimals
+	}
+	var steps []interface{}
+	colors := getList(panel, "colors")
+	if thresholds := getString(panel, "thresholds"); len(thresholds) > 0 && len(colors) > 0 {
+		steps = generateThresholds(strings.Split(thresholds, ","), colors)
+		defaults["thresholds"] = map[string]interface{}{"mode": "absolute", "steps": steps}
+	}
+	if mappings := convertValueMappings(panel, steps); len(mappings) > 0 {
+		defaults["mappings"] = mappings
+	}
+	for _, key := range []string{"prefix", "postfix"} {
+		if len(getString(panel, key)) > 0 {
+			warnings = append(warnings, fmt.Sprintf("the %s is not migrated", key))
+		}
+	}
+
+	fieldConfig := map[string]interface{}{"defaults": defaults, "overrides": []interface{}{}}
+	gauge := getObject(panel, "gauge")
+	if getBool(gauge, "show") {
+		if min, isNumber := getNumber(gauge, "minValue"); isNumber {
+			defaults["min"] = min
+		}
+		if max, isNumber := getNumber(gauge, "maxValue"); isNumber {
+			defaults["max"] = max
+		}
+		options["showThresholdMarkers"] = getBool(gauge, "thresholdMarkers")
+		options["showThresholdLabels"] = getBool(gauge, "thresholdLabels")
+		return "gauge", options, fieldConfig, warnings, nil
+	}
+
+	sparkline := getObject(panel, "sparkline")
+	options["graphMode"] = "none"
+	if getBool(sparkline, "show") {
+		options["graphMode"] = "area"
+	}
+	switch {
+	case getBool(panel, "colorBackground"):
+		options["colorMode"] = "background"
+	case getBool(panel, "colorValue"):
+		options["colorMode"] = "value"
+	default:
+		options["colorMode"] = "none"
+		if lineColor := getString(sparkline, "lineColor"); len(lineColor) > 0 && options["graphMode"] == "area" {
+			defaults["color"] = map[string]interface{}{"mode": "fixed", "fixedColor": lineColor}
+		}
+	}
+	options["justifyMode"] = "auto"
+	options["textMode"] = "auto"
+	if valueName == "name" {
+		options["textMode"] = "name"
+	}
+	return "stat", options, fieldConfig, warnings, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultStylePattern = "/.*/"
+
+var tableTransformations = map[string]string{
+	"timeseries_to_rows":      "seriesToRows",
+	"timeseries_to_columns":   "seriesToColumns",
+	"timeseries_aggregations": "reduce",
+	"table":                   "merge",
+}
+
+var tableColorModes = map[string]string{
+	"cell":  "color-background",
+	"row":   "color-background",
+	"value": "color-text",
+}
+
+// migrateTable converts a table-old panel into a table panel
+func migrateTable(panel map[string]interface{}) (string, map[string]interface{}, map[string]interface{}, []string, error) {
+	var warnings []string
+
+	transformations := make([]interface{}, 0)
+	transformations = append(transformations, getList(panel, "transformations")...)
+	transform := getString(panel, "transform")
+	if id, exist := tableTransformations[transform]; exist {
+		transformOptions := map[string]interface{}{"reducers": []interface{}{}}
+		if transform == "timeseries_aggregations" {
+			transformOptions["includeTimeField"] = false
+			columnReducers := make([]interface{}, 0)
+			for _, item := range getList(panel, "columns") {
+				column, _ := item.(map[string]interface{})
+				if reducer, valid := getReducer(getString(column, "value")); valid {
+					columnReducers = append(columnReducers, reducer)
+				}
+			}
+			transformOptions["reducers"] = columnReducers
+		}
+		transformations = append(transformations, map[string]interface{}{"id": id, "options": transformOptions})
+	} else if len(transform) > 0 {
+		warnings = append(warnings, fmt.Sprintf("the transform '%s' is not migrated", transform))
+	}
+
+	defaults := map[string]interface{}{"custom": map[string]interface{}{}}
+	overrides := make([]interface{}, 0)
+	for _, item := range getList(panel, "styles") {
+		style, _ := item.(map[string]interface{})
+		if style == nil {
+			continue
+		}
+		if getString(style, "pattern") == defaultStylePattern {
+			defaults = tableDefaults(style)
+			continue
+		}
+		overrides = append(overrides, tableStyleOverride(style))
+	}
+
+	if sortOption := getObject(panel, "sort"); sortOption != nil && sortOption["col"] != nil {
+		warnings = append(warnings, "the sort is not migrated since the columns are only known once the queries are executed")
+	}
+
+	// the transformations are part of the properties kept when the type of the panel changes
+	panel["transformations"] = transformations
+
+	options := map[string]interface{}{"showHeader": true}
+	fieldConfig := map[string]interface{}{"defaults": defaults, "overrides": overrides}
+	return "table", options, fieldConfig, warnings, nil
+}
+
+func tableDefaults(style map[string]interface{}) map[string]interface{} {
+	custom := make(map[string]interface{})
+	if align := getString(style, "align"); len(align) > 0 {
+		if align == "auto" {
+			custom["align"] = nil
+		} else {
+			custom["align"] = align
+		}
+	}
+	if displayMode, exist := tableColorModes[getString(style, "colorMode")]; exist {
+		custom["displayMode"] = displayMode
+	}
+	defaults := map[string]interface{}{"custom": custom}
+	if unit := getString(style, "unit"); len(unit) > 0 {
+		defaults["unit"] = unit
+	}
+	if decimals, isNumber := getNumber(style, "decimals"); isNumber {
+		defaults["decimals"] = decimals
+	}
+	if alias := getString(style, "alias"); len(alias) > 0 {
+		defaults["displayName"] = alias
+	}
+	if steps := tableThresholds(style); len(steps) > 0 {
+		defaults["thresholds"] = map[string]interface{}{"mode": "absolute", "steps": steps}
+	}
+	return defaults
+}
+
+func tableStyleOverride(style map[string]interface{}) map[string]interface{} {
+	properties := make([]interface{}, 0)
+	if alias := getString(style, "alias"); len(alias) > 0 {
+		properties = append(properties, property("displayName", alias))
+	}
+	if unit := getString(style, "unit"); len(unit) > 0 {
+		properties = append(properties, property("unit", unit))
+	}
+	if decimals, isNumber := getNumber(style, "decimals"); isNumber {
+		properties = append(properties, property("decimals", decimals))
+	}
+	switch getString(style, "type") {
+	case "date":
+		properties = append(properties, property("unit", "time: "+getString(style, "dateFormat")))
+	case "hidden":
+		properties = append(properties, property("custom.hidden", true))
+	}
+	if getBool(style, "link") {
+		title := getString(style, "linkTooltip")
+		url := strings.Replace(getString(style, "linkUrl"), "$__cell", "${__value.text}", -1)
+		properties = append(properties, property("links", []interface{}{
+			map[string]interface{}{"title": title, "url": url, "targetBlank": getBool(style, "linkTargetBlank")},
+		}))
+	}
+	if displayMode, exist := tableColorModes[getString(style, "colorMode")]; exist {
+		properties = append(properties, property("custom.displayMode", displayMode))
+	}
+	if align := getString(style, "align"); len(align) > 0 {
+		if align == "auto" {
+			properties = append(properties, property("custom.align", nil))
+		} else {
+			properties = append(properties, property("custom.align", align))
+		}
+	}
+	steps := tableThresholds(style)
+	if len(steps) > 0 {
+		properties = append(properties, property("thresholds", map[string]interface{}{"mode": "absolute", "steps": steps}))
+	}
+	if mappings := convertValueMappings(style, steps); len(mappings) > 0 {
+		properties = append(properties, property("mappings", mappings))
+	}
+	return newOverride(getString(style, "pattern"), properties)
+}
+
+func tableThresholds(style map[string]interface{}) []interface{} {
+	var levels []string
+	for _, level := range getList(style, "thresholds") {
+		levels = append(levels, toString(level))
+	}
+	if len(levels) == 0 {
+		return nil
+	}
+	return generateThresholds(levels, getList(style, "colors"))
+}
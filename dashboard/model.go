@@ -0,0 +1,300 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dashboard provides helpers to inspect and transform the JSON model of a Grafana dashboard.
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+const (
+	MixedDatasource     = "-- Mixed --"
+	GrafanaDatasource   = "-- Grafana --"
+	DashboardDatasource = "-- Dashboard --"
+)
+
+// DatasourceRef is a reference to a datasource as it can be found in a dashboard.
+// Since Grafana 8.3 a reference is an object containing the type and the uid of the datasource.
+// Before that, it was simply the name of the datasource.
+type DatasourceRef struct {
+	UID  string
+	Type string
+	// Name is only set when the reference is a legacy one, i.e. the name of the datasource
+	Name string
+}
+
+// IsVariable returns true if the reference points to a template variable like $datasource or ${DS_PROMETHEUS}
+func (r DatasourceRef) IsVariable() bool {
+	return strings.HasPrefix(r.UID, "$") || strings.HasPrefix(r.Name, "$")
+}
+
+// IsBuiltIn returns true if the reference points to one of the datasource provided by Grafana itself
+// (Mixed, Grafana or Dashboard)
+func (r DatasourceRef) IsBuiltIn() bool {
+	if r.Type == "datasource" {
+		return true
+	}
+	for _, value := range []string{r.UID, r.Name} {
+		switch value {
+		case MixedDatasource, GrafanaDatasource, DashboardDatasource, "grafana", "dashboard":
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns a string identifying the datasource referenced, i.e. the uid or the name if it's a legacy reference.
+func (r DatasourceRef) Key() string {
+	if len(r.UID) > 0 {
+		return r.UID
+	}
+	return r.Name
+}
+
+// Matches returns true if the reference points to the given datasource.
+// A legacy reference is compared with the name and the uid of the datasource
+// since some versions of Grafana stored the uid in place of the name.
+func (r DatasourceRef) Matches(ds *types.DataSource) bool {
+	if ds == nil {
+		return false
+	}
+	if len(r.UID) > 0 {
+		return r.UID == ds.UID
+	}
+	return len(r.Name) > 0 && (r.Name == ds.Name || r.Name == ds.UID)
+}
+
+// ParseDatasourceRef decodes the value of a field "datasource".
+// It returns false when the value doesn't reference any datasource, which means the default one is used.
+func ParseDatasourceRef(value interface{}) (DatasourceRef, bool) {
+	switch v := value.(type) {
+	case string:
+		if len(v) == 0 {
+			return DatasourceRef{}, false
+		}
+		return DatasourceRef{Name: v}, true
+	case map[string]interface{}:
+		ref := DatasourceRef{UID: getString(v, "uid"), Type: getString(v, "type")}
+		if len(ref.UID) == 0 {
+			return ref, false
+		}
+		return ref, true
+	}
+	return DatasourceRef{}, false
+}
+
+// FieldKind tells which part of the dashboard holds a field
+type FieldKind string
+
+const (
+	PanelField      FieldKind = "panel"
+	TargetField     FieldKind = "target"
+	VariableField   FieldKind = "variable"
+	AnnotationField FieldKind = "annotation"
+)
+
+// DatasourceField is a field "datasource" found in a dashboard
+type DatasourceField struct {
+	// Path is the JSON path of the field, like $.panels[2].targets[0].datasource
+	Path string
+	Kind FieldKind
+	Ref  DatasourceRef
+	// PanelID is the id of the panel holding the field. It's 0 for the variables and the annotations.
+	PanelID int64
+	parent  map[string]interface{}
+}
+
+// Value returns the raw value of the field
+func (f *DatasourceField) Value() interface{} {
+	return f.parent["datasource"]
+}
+
+// Set replaces the value of the field in the dashboard
+func (f *DatasourceField) Set(value interface{}) {
+	f.parent["datasource"] = value
+}
+
+// Parent returns the JSON object holding the field, i.e. the panel, the target, the variable or the annotation.
+func (f *DatasourceField) Parent() map[string]interface{} {
+	return f.parent
+}
+
+// WalkPanels calls fn for every panel of the dashboard, including the panels nested in a collapsed row
+// and the panels of the legacy rows used by dashboards with a schema version older than 16.
+// The walk stops at the first error returned by fn.
+func WalkPanels(model types.DashboardModel, fn func(path string, panel map[string]interface{}) error) error {
+	err := eachObject(model, "panels", func(i int, panel map[string]interface{}) error {
+		path := fmt.Sprintf("$.panels[%d]", i)
+		if err := fn(path, panel); err != nil {
+			return err
+		}
+		return eachObject(panel, "panels", func(j int, nested map[string]interface{}) error {
+			return fn(fmt.Sprintf("%s.panels[%d]", path, j), nested)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return eachObject(model, "rows", func(i int, row map[string]interface{}) error {
+		return eachObject(row, "panels", func(j int, panel map[string]interface{}) error {
+			return fn(fmt.Sprintf("$.rows[%d].panels[%d]", i, j), panel)
+		})
+	})
+}
+
+// Panels returns every panel of the dashboard. See WalkPanels to know which panels are considered.
+func Panels(model types.DashboardModel) []map[string]interface{} {
+	var result []map[string]interface{}
+	_ = WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		result = append(result, panel)
+		return nil
+	})
+	return result
+}
+
+// VariableReferencePattern returns the regular expression matching a reference to the variable, whatever the syntax used:
+// $name, ${name}, ${name:format}, [[name]] or [[name:format]]
+func VariableReferencePattern(name string) string {
+	quoted := regexp.QuoteMeta(name)
+	return fmt.Sprintf(`\$%[1]s\b|\$\{%[1]s(?::[^}]*)?\}|\[\[%[1]s(?::[^\]]*)?\]\]`, quoted)
+}
+
+// Variables returns the template variables of the dashboard
+func Variables(model types.DashboardModel) []map[string]interface{} {
+	templating, _ := model["templating"].(map[string]interface{})
+	return getObjectList(templating, "list")
+}
+
+// Annotations returns the annotation queries of the dashboard
+func Annotations(model types.DashboardModel) []map[string]interface{} {
+	annotations, _ := model["annotations"].(map[string]interface{})
+	return getObjectList(annotations, "list")
+}
+
+// Targets returns the queries of a panel
+func Targets(panel map[string]interface{}) []map[string]interface{} {
+	return getObjectList(panel, "targets")
+}
+
+// PanelID returns the id of the panel or 0 if it's not set
+func PanelID(panel map[string]interface{}) int64 {
+	return getInt64(panel, "id")
+}
+
+// PanelType returns the type of the panel
+func PanelType(panel map[string]interface{}) string {
+	return getString(panel, "type")
+}
+
+// DatasourceFields returns every field "datasource" of the dashboard that references explicitly a datasource.
+// A field with a null value isn't returned since it means the panel or the query uses the default datasource.
+func DatasourceFields(model types.DashboardModel) []*DatasourceField {
+	var result []*DatasourceField
+	appendField := func(path string, kind FieldKind, panelID int64, parent map[string]interface{}) {
+		if ref, ok := ParseDatasourceRef(parent["datasource"]); ok {
+			result = append(result, &DatasourceField{
+				Path:    path + ".datasource",
+				Kind:    kind,
+				Ref:     ref,
+				PanelID: panelID,
+				parent:  parent,
+			})
+		}
+	}
+
+	_ = WalkPanels(model, func(path string, panel map[string]interface{}) error {
+		panelID := PanelID(panel)
+		appendField(path, PanelField, panelID, panel)
+		return eachObject(panel, "targets", func(i int, target map[string]interface{}) error {
+			appendField(fmt.Sprintf("%s.targets[%d]", path, i), TargetField, panelID, target)
+			return nil
+		})
+	})
+	templating, _ := model["templating"].(map[string]interface{})
+	_ = eachObject(templating, "list", func(i int, variable map[string]interface{}) error {
+		appendField(fmt.Sprintf("$.templating.list[%d]", i), VariableField, 0, variable)
+		return nil
+	})
+	annotations, _ := model["annotations"].(map[string]interface{})
+	_ = eachObject(annotations, "list", func(i int, annotation map[string]interface{}) error {
+		appendField(fmt.Sprintf("$.annotations.list[%d]", i), AnnotationField, 0, annotation)
+		return nil
+	})
+	return result
+}
+
+// Copy returns a deep copy of the dashboard
+func Copy(model types.DashboardModel) (types.DashboardModel, error) {
+	result := make(types.DashboardModel)
+	if err := convert(model, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// convert transforms the value from into the value to using their JSON representation
+func convert(from interface{}, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// toJSONValue transforms any value into its generic JSON representation (map, slice, string, float64, bool or nil)
+func toJSONValue(value interface{}) (interface{}, error) {
+	var result interface{}
+	err := convert(value, &result)
+	return result, err
+}
+
+func getString(object map[string]interface{}, key string) string {
+	value, _ := object[key].(string)
+	return value
+}
+
+func getInt64(object map[string]interface{}, key string) int64 {
+	value, _ := object[key].(float64)
+	return int64(value)
+}
+
+func getObjectList(object map[string]interface{}, key string) []map[string]interface{} {
+	list, _ := object[key].([]interface{})
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if obj, ok := item.(map[string]interface{}); ok {
+			result = append(result, obj)
+		}
+	}
+	return result
+}
+
+// eachObject calls fn for every JSON object contained in the list object[key].
+// The index passed to fn is the position of the object in the list.
+func eachObject(object map[string]interface{}, key string, fn func(i int, obj map[string]interface{}) error) error {
+	list, _ := object[key].([]interface{})
+	for i, item := range list {
+		if obj, ok := item.(map[string]interface{}); ok {
+			if err := fn(i, obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
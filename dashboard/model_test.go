@@ -0,0 +1,44 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariableReferencePattern(t *testing.T) {
+	reference := regexp.MustCompile(VariableReferencePattern("job"))
+	testSuites := []struct {
+		text     string
+		expected bool
+	}{
+		{text: "up{job=\"$job\"}", expected: true},
+		{text: "$job", expected: true},
+		{text: "${job}", expected: true},
+		{text: "${job:regex}", expected: true},
+		{text: "[[job]]", expected: true},
+		{text: "[[job:csv]]", expected: true},
+		{text: "$jobs", expected: false},
+		{text: "${jobs}", expected: false},
+		{text: "job", expected: false},
+	}
+	for _, test := range testSuites {
+		t.Run(test.text, func(t *testing.T) {
+			assert.Equal(t, test.expected, reference.MatchString(test.text))
+		})
+	}
+}
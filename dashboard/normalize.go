@@ -0,0 +1,232 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// NormalizeOptions configures what is removed or reordered to get the canonical form of a dashboard
+type NormalizeOptions struct {
+	// Fields are the fields of the dashboard removed, like id or version
+	Fields []string
+	// PanelFields are the fields removed from every panel, like pluginVersion
+	PanelFields []string
+	// RecursiveFields are removed at any level of the dashboard, like the $$hashKey added by the old Angular UI
+	RecursiveFields []string
+	// RemoveDefaults removes the fields having the value Grafana uses when they are missing. It removes as well the null fields
+	// of the dashboard, the panels, the queries and the variables. The null values nested deeper are kept since some are
+	// meaningful, like the value of the base step of the thresholds.
+	RemoveDefaults bool
+	// SortPanels sorts the panels by position (top to bottom, then left to right) like Grafana does when it saves a dashboard
+	SortPanels bool
+	// StripPanelIDs removes the ids of the panels. The ids are used by the links to a single panel and by the alert rules.
+	StripPanelIDs bool
+	// StripVariableOptions removes the options of the variables that Grafana refreshes when the dashboard is loaded
+	StripVariableOptions bool
+}
+
+// DefaultNormalizeOptions returns the options removing the fields changed by Grafana at every save
+// and the fields having their default value, and sorting the panels.
+func DefaultNormalizeOptions() NormalizeOptions {
+	return NormalizeOptions{
+		Fields:               []string{"id", "version", "iteration"},
+		PanelFields:          []string{"pluginVersion"},
+		RecursiveFields:      []string{"$$hashKey"},
+		RemoveDefaults:       true,
+		SortPanels:           true,
+		StripVariableOptions: true,
+	}
+}
+
+var dashboardDefaults = map[string]interface{}{
+	"editable":             true,
+	"graphTooltip":         float64(0),
+	"links":                []interface{}{},
+	"liveNow":              false,
+	"fiscalYearStartMonth": float64(0),
+	"weekStart":            "",
+	"refresh":              "",
+	"tags":                 []interface{}{},
+	"style":                "dark",
+}
+
+var panelDefaults = map[string]interface{}{
+	"transparent":      false,
+	"links":            []interface{}{},
+	"transformations":  []interface{}{},
+	"hideTimeOverride": false,
+	"description":      "",
+	"repeatDirection":  "h",
+}
+
+var targetDefaults = map[string]interface{}{
+	"hide": false,
+}
+
+var variableDefaults = map[string]interface{}{
+	"hide":        float64(0),
+	"skipUrlSync": false,
+	"description": "",
+	"label":       "",
+}
+
+// Normalize returns the canonical form of the dashboard. The dashboard given is not modified.
+func Normalize(model types.DashboardModel, options NormalizeOptions) (types.DashboardModel, error) {
+	result, err := Copy(model)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range options.Fields {
+		delete(result, field)
+	}
+	if len(options.RecursiveFields) > 0 {
+		removeRecursively(map[string]interface{}(result), options.RecursiveFields)
+	}
+
+	normalizePanels(result, options)
+	_ = WalkPanels(result, func(path string, panel map[string]interface{}) error {
+		for _, field := range options.PanelFields {
+			delete(panel, field)
+		}
+		if options.StripPanelIDs {
+			delete(panel, "id")
+		}
+		normalizePanels(panel, options)
+		if options.RemoveDefaults {
+			removeDefaults(panel, panelDefaults)
+			removeNulls(panel)
+			_ = eachObject(panel, "targets", func(_ int, target map[string]interface{}) error {
+				removeDefaults(target, targetDefaults)
+				removeNulls(target)
+				return nil
+			})
+		}
+		return nil
+	})
+
+	templating, _ := result["templating"].(map[string]interface{})
+	_ = eachObject(templating, "list", func(_ int, variable map[string]interface{}) error {
+		if options.StripVariableOptions && getString(variable, "type") == "query" && getInt64(variable, "refresh") > 0 {
+			delete(variable, "options")
+		}
+		if options.RemoveDefaults {
+			removeDefaults(variable, variableDefaults)
+			removeNulls(variable)
+		}
+		return nil
+	})
+	if options.RemoveDefaults {
+		removeDefaults(result, dashboardDefaults)
+		removeNulls(result)
+	}
+	return result, nil
+}
+
+// CanonicalJSON returns the JSON of the normalized dashboard. The keys are sorted and there is no indentation,
+// so two dashboards with the same content have the same JSON.
+func CanonicalJSON(model types.DashboardModel, options NormalizeOptions) ([]byte, error) {
+	normalized, err := Normalize(model, options)
+	if err != nil {
+		return nil, err
+	}
+	// the keys of a map are sorted by the encoder
+	return json.Marshal(normalized)
+}
+
+// Hash returns the SHA-256, encoded in hexadecimal, of the canonical JSON of the dashboard
+func Hash(model types.DashboardModel, options NormalizeOptions) (string, error) {
+	data, err := CanonicalJSON(model, options)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Equivalent returns true if the two dashboards have the same canonical form
+func Equivalent(a types.DashboardModel, b types.DashboardModel, options NormalizeOptions) (bool, error) {
+	hashA, err := Hash(a, options)
+	if err != nil {
+		return false, err
+	}
+	hashB, err := Hash(b, options)
+	if err != nil {
+		return false, err
+	}
+	return hashA == hashB, nil
+}
+
+// normalizePanels sorts the panels directly held by the object (the dashboard or a collapsed row)
+func normalizePanels(object map[string]interface{}, options NormalizeOptions) {
+	panels, isList := object["panels"].([]interface{})
+	if !isList || !options.SortPanels {
+		return
+	}
+	sort.SliceStable(panels, func(i, j int) bool {
+		yi, xi := gridPosition(panels[i])
+		yj, xj := gridPosition(panels[j])
+		if yi != yj {
+			return yi < yj
+		}
+		return xi < xj
+	})
+}
+
+func gridPosition(panel interface{}) (float64, float64) {
+	object, _ := panel.(map[string]interface{})
+	position, _ := object["gridPos"].(map[string]interface{})
+	y, _ := position["y"].(float64)
+	x, _ := position["x"].(float64)
+	return y, x
+}
+
+func removeDefaults(object map[string]interface{}, defaults map[string]interface{}) {
+	for key, defaultValue := range defaults {
+		if value, exist := object[key]; exist && reflect.DeepEqual(value, defaultValue) {
+			delete(object, key)
+		}
+	}
+}
+
+// removeNulls removes the null fields of the object, without going into the nested objects
+func removeNulls(object map[string]interface{}) {
+	for key, value := range object {
+		if value == nil {
+			delete(object, key)
+		}
+	}
+}
+
+func removeRecursively(value interface{}, fields []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, field := range fields {
+			delete(v, field)
+		}
+		for _, item := range v {
+			removeRecursively(item, fields)
+		}
+	case []interface{}:
+		for _, item := range v {
+			removeRecursively(item, fields)
+		}
+	}
+}
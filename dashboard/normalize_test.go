@@ -0,0 +1,105 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	model := newTestDashboard(t, `{
+  "id": 42, "uid": "abcd", "version": 7, "iteration": 1600000000000, "title": "my dashboard", "editable": true, "links": [],
+  "panels": [
+    {"id": 2, "type": "stat", "gridPos": {"x": 12, "y": 0, "w": 12, "h": 8}, "pluginVersion": "9.0.0", "transparent": false,
+     "targets": [{"refId": "A", "hide": false, "$$hashKey": "object:12"}], "timeFrom": null},
+    {"id": 1, "type": "timeseries", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8}, "transparent": true}
+  ],
+  "templating": {"list": [
+    {"name": "job", "type": "query", "refresh": 1, "hide": 0, "options": [{"text": "node", "value": "node"}], "current": {"text": "node", "value": "node"}}
+  ]}
+}`)
+
+	result, err := Normalize(model, DefaultNormalizeOptions())
+	assert.Nil(t, err)
+	assert.Equal(t, newTestDashboard(t, `{
+  "uid": "abcd", "title": "my dashboard",
+  "panels": [
+    {"id": 1, "type": "timeseries", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8}, "transparent": true},
+    {"id": 2, "type": "stat", "gridPos": {"x": 12, "y": 0, "w": 12, "h": 8}, "targets": [{"refId": "A"}]}
+  ],
+  "templating": {"list": [
+    {"name": "job", "type": "query", "refresh": 1, "current": {"text": "node", "value": "node"}}
+  ]}
+}`), result)
+	// the dashboard given is not modified
+	assert.Equal(t, float64(42), model["id"])
+}
+
+func TestNormalize_KeepsNestedNulls(t *testing.T) {
+	model := newTestDashboard(t, `{"uid": "abcd", "title": "my dashboard", "gnetId": null, "panels": [
+    {"id": 1, "type": "stat", "timeShift": null, "targets": [{"refId": "A", "interval": null}],
+     "fieldConfig": {"defaults": {"thresholds": {"mode": "absolute", "steps": [{"color": "green", "value": null}, {"color": "red", "value": 80}]}}}}
+  ]}`)
+
+	result, err := Normalize(model, DefaultNormalizeOptions())
+	assert.Nil(t, err)
+	assert.Equal(t, newTestDashboard(t, `{"uid": "abcd", "title": "my dashboard", "panels": [
+    {"id": 1, "type": "stat", "targets": [{"refId": "A"}],
+     "fieldConfig": {"defaults": {"thresholds": {"mode": "absolute", "steps": [{"color": "green", "value": null}, {"color": "red", "value": 80}]}}}}
+  ]}`), result)
+
+	// the base step of the thresholds is not the same as a step without value
+	withoutBase := newTestDashboard(t, `{"uid": "abcd", "title": "my dashboard", "panels": [
+    {"id": 1, "type": "stat", "targets": [{"refId": "A"}],
+     "fieldConfig": {"defaults": {"thresholds": {"mode": "absolute", "steps": [{"color": "green"}, {"color": "red", "value": 80}]}}}}
+  ]}`)
+	equivalent, err := Equivalent(model, withoutBase, DefaultNormalizeOptions())
+	assert.Nil(t, err)
+	assert.False(t, equivalent)
+}
+
+func TestHash(t *testing.T) {
+	fromGit := newTestDashboard(t, `{"uid": "abcd", "title": "my dashboard", "panels": [
+    {"id": 1, "type": "timeseries", "title": "cpu", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8}},
+    {"id": 2, "type": "stat", "title": "memory", "gridPos": {"x": 12, "y": 0, "w": 12, "h": 8}}
+  ]}`)
+	fromGrafana := newTestDashboard(t, `{"version": 12, "id": 4, "title": "my dashboard", "uid": "abcd", "editable": true, "panels": [
+    {"title": "memory", "type": "stat", "id": 2, "gridPos": {"y": 0, "x": 12, "h": 8, "w": 12}, "pluginVersion": "10.0.0"},
+    {"type": "timeseries", "id": 1, "title": "cpu", "links": [], "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8}}
+  ]}`)
+	changed := newTestDashboard(t, `{"uid": "abcd", "title": "my dashboard", "panels": [
+    {"id": 1, "type": "timeseries", "title": "cpu usage", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8}},
+    {"id": 2, "type": "stat", "title": "memory", "gridPos": {"x": 12, "y": 0, "w": 12, "h": 8}}
+  ]}`)
+
+	options := DefaultNormalizeOptions()
+	hash, err := Hash(fromGit, options)
+	assert.Nil(t, err)
+	assert.Len(t, hash, 64)
+
+	equivalent, err := Equivalent(fromGit, fromGrafana, options)
+	assert.Nil(t, err)
+	assert.True(t, equivalent)
+
+	equivalent, err = Equivalent(fromGit, changed, options)
+	assert.Nil(t, err)
+	assert.False(t, equivalent)
+
+	// without normalization, the volatile fields make the dashboards different
+	equivalent, err = Equivalent(fromGit, fromGrafana, NormalizeOptions{})
+	assert.Nil(t, err)
+	assert.False(t, equivalent)
+}
@@ -0,0 +1,355 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// ErrTestFailed is wrapped by the PatchError returned when an operation "test" of a JSON Patch fails
+var ErrTestFailed = errors.New("test operation failed")
+
+// PatchError is returned when an operation of a JSON Patch can't be applied
+type PatchError struct {
+	// Index is the position of the operation in the patch
+	Index     int
+	Operation types.JSONPatchOperation
+	Err       error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("unable to apply the operation %d (%s %s): %s", e.Index, e.Operation.Op, e.Operation.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// Patch fetches the dashboard, applies a JSON Patch and/or a JSON Merge Patch and saves it with DashboardInterface.Modify,
+// so a concurrent modification is never overwritten. The changes are returned even when it's a dry run.
+func Patch(client api.ClientInterface, uid string, patch *types.DashboardPatch) (*types.DashboardPatchResult, error) {
+	result := &types.DashboardPatchResult{}
+	saved, err := client.Dashboards().Modify(uid, patch.Message, func(model types.DashboardModel) error {
+		patched, err := JSONPatch(model, patch.JSONPatch)
+		if err != nil {
+			return err
+		}
+		if patch.MergePatch != nil {
+			if patched, err = MergePatch(patched, patch.MergePatch); err != nil {
+				return err
+			}
+		}
+		if result.Changes, err = Diff(model, patched); err != nil {
+			return err
+		}
+		result.Dashboard = patched
+		if patch.DryRun || len(result.Changes) == 0 {
+			return api.ErrNotModified
+		}
+		replaceModel(model, patched)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Saved = saved
+	return result, nil
+}
+
+// replaceModel replaces the content of the dashboard by the content of the other one
+func replaceModel(model types.DashboardModel, other types.DashboardModel) {
+	for key := range model {
+		delete(model, key)
+	}
+	for key, value := range other {
+		model[key] = value
+	}
+}
+
+// JSONPatch applies a JSON Patch (RFC 6902) to the dashboard and returns the result.
+// The patch is atomic: if an operation fails, an error is returned and the dashboard passed as a parameter is not modified.
+func JSONPatch(model types.DashboardModel, operations []types.JSONPatchOperation) (types.DashboardModel, error) {
+	doc, err := toJSONValue(model)
+	if err != nil {
+		return nil, err
+	}
+	for i, operation := range operations {
+		doc, err = applyOperation(doc, operation)
+		if err != nil {
+			return nil, &PatchError{Index: i, Operation: operation, Err: err}
+		}
+	}
+	result, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the patch doesn't produce a JSON object")
+	}
+	return result, nil
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to the dashboard and returns the result.
+// The dashboard passed as a parameter is not modified.
+func MergePatch(model types.DashboardModel, patch map[string]interface{}) (types.DashboardModel, error) {
+	doc, err := toJSONValue(model)
+	if err != nil {
+		return nil, err
+	}
+	patchValue, err := toJSONValue(patch)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := mergePatch(doc, patchValue).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the patch doesn't produce a JSON object")
+	}
+	return result, nil
+}
+
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, isObject := patch.(map[string]interface{})
+	if !isObject {
+		return patch
+	}
+	targetObject, isObject := target.(map[string]interface{})
+	if !isObject {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+func applyOperation(doc interface{}, operation types.JSONPatchOperation) (interface{}, error) {
+	tokens, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := toJSONValue(operation.Value)
+	if err != nil {
+		return nil, err
+	}
+	switch operation.Op {
+	case "add":
+		return addValue(doc, tokens, value)
+	case "remove":
+		result, _, err := removeValue(doc, tokens)
+		return result, err
+	case "replace":
+		if _, err := getValue(doc, tokens); err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			return value, nil
+		}
+		return update(doc, tokens, func(container interface{}, key string) (interface{}, error) {
+			switch c := container.(type) {
+			case map[string]interface{}:
+				c[key] = value
+				return c, nil
+			case []interface{}:
+				index, err := parseIndex(key, len(c)-1)
+				if err != nil {
+					return nil, err
+				}
+				c[index] = value
+				return c, nil
+			}
+			return nil, fmt.Errorf("unable to find the value to replace")
+		})
+	case "move":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if operation.From == operation.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(operation.Path, operation.From+"/") {
+			return nil, fmt.Errorf("a value can't be moved into one of its children")
+		}
+		doc, moved, err := removeValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, tokens, moved)
+	case "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		// the value is duplicated so the copy and the original value don't share the same map or slice
+		if copied, err = toJSONValue(copied); err != nil {
+			return nil, err
+		}
+		return addValue(doc, tokens, copied)
+	case "test":
+		current, err := getValue(doc, tokens)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrTestFailed, err)
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("%w: the current value is %v", ErrTestFailed, current)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation '%s'", operation.Op)
+}
+
+// parsePointer decodes a JSON Pointer as defined by the RFC 6901
+func parsePointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("the JSON pointer '%s' must start with a '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// parseIndex decodes an index of an array. max is the highest index accepted.
+func parseIndex(token string, max int) (int, error) {
+	if len(token) == 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("'%s' is not a valid array index", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("'%s' is not a valid array index", token)
+	}
+	if index > max {
+		return 0, fmt.Errorf("the index %d is out of bounds", index)
+	}
+	return index, nil
+}
+
+func getValue(doc interface{}, tokens []string) (interface{}, error) {
+	current := doc
+	for _, token := range tokens {
+		switch c := current.(type) {
+		case map[string]interface{}:
+			value, exist := c[token]
+			if !exist {
+				return nil, fmt.Errorf("the key '%s' doesn't exist", token)
+			}
+			current = value
+		case []interface{}:
+			index, err := parseIndex(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = c[index]
+		default:
+			return nil, fmt.Errorf("unable to find '%s' in a value that is neither an object nor an array", token)
+		}
+	}
+	return current, nil
+}
+
+// update walks the document until the container of the last token, then calls fn with this container.
+// The container returned by fn replaces the previous one, which is required when the size of an array is changed.
+func update(doc interface{}, tokens []string, fn func(container interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+	child, err := getValue(doc, tokens[:1])
+	if err != nil {
+		return nil, err
+	}
+	newChild, err := update(child, tokens[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		c[tokens[0]] = newChild
+	case []interface{}:
+		index, _ := strconv.Atoi(tokens[0])
+		c[index] = newChild
+	}
+	return doc, nil
+}
+
+func addValue(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(doc, tokens, func(container interface{}, key string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[key] = value
+			return c, nil
+		case []interface{}:
+			index := len(c)
+			if key != "-" {
+				var err error
+				if index, err = parseIndex(key, len(c)); err != nil {
+					return nil, err
+				}
+			}
+			result := make([]interface{}, 0, len(c)+1)
+			result = append(result, c[:index]...)
+			result = append(result, value)
+			return append(result, c[index:]...), nil
+		}
+		return nil, fmt.Errorf("unable to add a value in something that is neither an object nor an array")
+	})
+}
+
+func removeValue(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("the whole document can't be removed")
+	}
+	var removed interface{}
+	result, err := update(doc, tokens, func(container interface{}, key string) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			value, exist := c[key]
+			if !exist {
+				return nil, fmt.Errorf("the key '%s' doesn't exist", key)
+			}
+			removed = value
+			delete(c, key)
+			return c, nil
+		case []interface{}:
+			index, err := parseIndex(key, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[index]
+			result := make([]interface{}, 0, len(c)-1)
+			result = append(result, c[:index]...)
+			return append(result, c[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("unable to remove a value from something that is neither an object nor an array")
+	})
+	return result, removed, err
+}
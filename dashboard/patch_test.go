@@ -0,0 +1,126 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const patchTestDashboard = `{
+  "uid": "abcd",
+  "title": "my dashboard",
+  "tags": ["prod", "team-a"],
+  "panels": [
+    {"id": 1, "title": "CPU", "fieldConfig": {"defaults": {"thresholds": {"steps": [{"color": "green", "value": null}, {"color": "red", "value": 80}]}}}},
+    {"id": 2, "title": "Memory"}
+  ],
+  "a/b": {"c~d": 1}
+}`
+
+func TestJSONPatch(t *testing.T) {
+	testSuites := []struct {
+		title         string
+		operations    []types.JSONPatchOperation
+		expected      string
+		expectedError error
+	}{
+		{
+			title: "test and replace",
+			operations: []types.JSONPatchOperation{
+				{Op: "test", Path: "/panels/0/fieldConfig/defaults/thresholds/steps/1/value", Value: 80},
+				{Op: "replace", Path: "/panels/0/fieldConfig/defaults/thresholds/steps/1/value", Value: 90},
+			},
+			expected: `{"uid": "abcd", "title": "my dashboard", "tags": ["prod", "team-a"], "a/b": {"c~d": 1}, "panels": [
+              {"id": 1, "title": "CPU", "fieldConfig": {"defaults": {"thresholds": {"steps": [{"color": "green", "value": null}, {"color": "red", "value": 90}]}}}},
+              {"id": 2, "title": "Memory"}]}`,
+		},
+		{
+			title: "add, remove, move and copy",
+			operations: []types.JSONPatchOperation{
+				{Op: "add", Path: "/tags/-", Value: "new"},
+				{Op: "add", Path: "/tags/0", Value: "first"},
+				{Op: "remove", Path: "/tags/1"},
+				{Op: "remove", Path: "/panels/0/fieldConfig"},
+				{Op: "move", From: "/panels/1/title", Path: "/description"},
+				{Op: "copy", From: "/a~1b/c~0d", Path: "/panels/1/id"},
+			},
+			expected: `{"uid": "abcd", "title": "my dashboard", "tags": ["first", "team-a", "new"], "a/b": {"c~d": 1},
+              "description": "Memory", "panels": [{"id": 1, "title": "CPU"}, {"id": 1}]}`,
+		},
+		{
+			title: "precondition failed",
+			operations: []types.JSONPatchOperation{
+				{Op: "replace", Path: "/title", Value: "other title"},
+				{Op: "test", Path: "/panels/0/title", Value: "Memory"},
+			},
+			expectedError: ErrTestFailed,
+		},
+		{
+			title: "precondition on a missing value",
+			operations: []types.JSONPatchOperation{
+				{Op: "test", Path: "/panels/3/title", Value: "Memory"},
+			},
+			expectedError: ErrTestFailed,
+		},
+		{
+			title: "replace a missing value",
+			operations: []types.JSONPatchOperation{
+				{Op: "replace", Path: "/missing", Value: "value"},
+			},
+			expectedError: errors.New("the key 'missing' doesn't exist"),
+		},
+		{
+			title: "index out of bounds",
+			operations: []types.JSONPatchOperation{
+				{Op: "add", Path: "/tags/3", Value: "value"},
+			},
+			expectedError: errors.New("the index 3 is out of bounds"),
+		},
+	}
+
+	for _, testSuite := range testSuites {
+		model := newTestDashboard(t, patchTestDashboard)
+		result, err := JSONPatch(model, testSuite.operations)
+		if testSuite.expectedError != nil {
+			assert.NotNil(t, err, testSuite.title)
+			patchErr, ok := err.(*PatchError)
+			assert.True(t, ok, testSuite.title)
+			if testSuite.expectedError == ErrTestFailed {
+				assert.True(t, errors.Is(err, ErrTestFailed), testSuite.title)
+			} else {
+				assert.Equal(t, testSuite.expectedError.Error(), patchErr.Err.Error(), testSuite.title)
+			}
+			continue
+		}
+		assert.Nil(t, err, testSuite.title)
+		assert.Equal(t, newTestDashboard(t, testSuite.expected), result, testSuite.title)
+		// the initial dashboard must not be modified
+		assert.Equal(t, newTestDashboard(t, patchTestDashboard), model, testSuite.title)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	model := newTestDashboard(t, `{"title": "my dashboard", "tags": ["prod"], "time": {"from": "now-6h", "to": "now"}, "refresh": "5m"}`)
+	result, err := MergePatch(model, map[string]interface{}{
+		"tags":    []string{"production"},
+		"time":    map[string]interface{}{"from": "now-1h"},
+		"refresh": nil,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, newTestDashboard(t, `{"title": "my dashboard", "tags": ["production"], "time": {"from": "now-1h", "to": "now"}}`), result)
+}
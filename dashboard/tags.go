@@ -0,0 +1,179 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// similarPrefixMinLength is the minimal length of a tag considered as an abbreviation of another one, like prod for production
+const similarPrefixMinLength = 3
+
+// Tags returns the tags of the dashboard
+func Tags(model types.DashboardModel) []string {
+	list, _ := model["tags"].([]interface{})
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if tag, isString := item.(string); isString {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func setTags(model types.DashboardModel, tags []string) {
+	list := make([]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		list = append(list, tag)
+	}
+	model["tags"] = list
+}
+
+// AddTags adds the tags missing in the dashboard. It returns true if the dashboard has been modified.
+func AddTags(model types.DashboardModel, tags ...string) bool {
+	current := Tags(model)
+	modified := false
+	for _, tag := range tags {
+		if len(tag) > 0 && !containsString(current, tag) {
+			current = append(current, tag)
+			modified = true
+		}
+	}
+	if modified {
+		setTags(model, current)
+	}
+	return modified
+}
+
+// RemoveTags removes the tags from the dashboard. It returns true if the dashboard has been modified.
+func RemoveTags(model types.DashboardModel, tags ...string) bool {
+	current := Tags(model)
+	result := make([]string, 0, len(current))
+	for _, tag := range current {
+		if !containsString(tags, tag) {
+			result = append(result, tag)
+		}
+	}
+	if len(result) == len(current) {
+		return false
+	}
+	setTags(model, result)
+	return true
+}
+
+// RenameTag replaces the tag from by the tag to, keeping its position. If the dashboard already has the tag to,
+// the tag from is simply removed. It returns true if the dashboard has been modified.
+func RenameTag(model types.DashboardModel, from string, to string) bool {
+	current := Tags(model)
+	if from == to || !containsString(current, from) {
+		return false
+	}
+	hasTarget := containsString(current, to)
+	result := make([]string, 0, len(current))
+	for _, tag := range current {
+		switch {
+		case tag != from:
+			result = append(result, tag)
+		case !hasTarget:
+			result = append(result, to)
+			hasTarget = true
+		}
+	}
+	setTags(model, result)
+	return true
+}
+
+// SimilarTags groups the tags that are probably duplicates: the ones that differ only by the case or the punctuation,
+// like Prod and prod or team-a and team_a, and the ones where one is the abbreviation of the other, like prod and production.
+// Only the groups having at least two tags are returned, each one sorted, the most used tags first if counts are given.
+func SimilarTags(tags []*types.DashboardTags) [][]*types.DashboardTags {
+	parent := make([]int, len(tags))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tagKey(tag.Term)
+	}
+	for i := range tags {
+		for j := i + 1; j < len(tags); j++ {
+			if similarKeys(keys[i], keys[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]*types.DashboardTags)
+	for i, tag := range tags {
+		root := find(i)
+		groups[root] = append(groups[root], tag)
+	}
+	var result [][]*types.DashboardTags
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Count != group[j].Count {
+				return group[i].Count > group[j].Count
+			}
+			return group[i].Term < group[j].Term
+		})
+		result = append(result, group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0].Term < result[j][0].Term
+	})
+	return result
+}
+
+// tagKey returns the tag in lower case without the characters that are not letters or digits
+func tagKey(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, tag)
+}
+
+func similarKeys(a string, b string) bool {
+	if a == b {
+		return len(a) > 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return len(a) >= similarPrefixMinLength && strings.HasPrefix(b, a)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	model := newTestDashboard(t, `{"tags": ["prod", "team-a"]}`)
+
+	assert.False(t, AddTags(model, "prod"))
+	assert.True(t, AddTags(model, "backend", "prod"))
+	assert.Equal(t, []string{"prod", "team-a", "backend"}, Tags(model))
+
+	assert.True(t, RenameTag(model, "prod", "production"))
+	assert.Equal(t, []string{"production", "team-a", "backend"}, Tags(model))
+	assert.False(t, RenameTag(model, "prod", "production"))
+	assert.True(t, RenameTag(model, "team-a", "backend"))
+	assert.Equal(t, []string{"production", "backend"}, Tags(model))
+
+	assert.False(t, RemoveTags(model, "unknown"))
+	assert.True(t, RemoveTags(model, "backend", "unknown"))
+	assert.Equal(t, []string{"production"}, Tags(model))
+}
+
+func TestSimilarTags(t *testing.T) {
+	groups := SimilarTags([]*types.DashboardTags{
+		{Term: "prod", Count: 3},
+		{Term: "production", Count: 12},
+		{Term: "Prod", Count: 1},
+		{Term: "team-a", Count: 2},
+		{Term: "team_a", Count: 4},
+		{Term: "k8s", Count: 5},
+		{Term: "db", Count: 1},
+		{Term: "dbaas", Count: 1},
+	})
+	var terms [][]string
+	for _, group := range groups {
+		var names []string
+		for _, tag := range group {
+			names = append(names, tag.Term)
+		}
+		terms = append(terms, names)
+	}
+	assert.Equal(t, [][]string{{"production", "prod", "Prod"}, {"team_a", "team-a"}}, terms)
+}
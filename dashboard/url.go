@@ -0,0 +1,273 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+type Theme string
+
+const (
+	LightTheme Theme = "light"
+	DarkTheme  Theme = "dark"
+)
+
+type KioskMode string
+
+const (
+	// KioskFull hides the navigation and the variables
+	KioskFull KioskMode = "1"
+	// KioskTV hides only the navigation. This mode doesn't exist anymore in the recent versions of Grafana.
+	KioskTV KioskMode = "tv"
+)
+
+const variablePrefix = "var-"
+
+// URL builds the link of a dashboard. The zero value of each field means the parameter is not set.
+type URL struct {
+	// BaseURL is the root URL of Grafana including its sub path, like https://grafana.example.com/grafana.
+	// When it's empty, the URL built is relative to the root of Grafana.
+	BaseURL string
+	UID     string
+	// Slug is optional, Grafana redirects to the right slug if it's missing or wrong
+	Slug  string
+	OrgID int64
+	// From and To are the time range, either relative like now-6h or an epoch in milliseconds
+	From string
+	To   string
+	// Variables contains the values of the template variables, by variable name
+	Variables map[string][]string
+	// ViewPanel is the ID of the single panel to display
+	ViewPanel int64
+	Kiosk     KioskMode
+	Theme     Theme
+}
+
+// NewURL returns the URL of the dashboard
+func NewURL(uid string, slug string) *URL {
+	return &URL{UID: uid, Slug: slug}
+}
+
+// ParseURL reads the URL of a dashboard, like the field URL of a types.SearchResult.
+// The base URL and the parameters already set in the URL are kept.
+func ParseURL(rawURL string) (*URL, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	// the scan starts from the base path so a dashboard whose UID is d isn't mistaken for the d segment
+	index := -1
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "d" && len(segments[i+1]) > 0 {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("'%s' is not the URL of a dashboard", rawURL)
+	}
+	result := &URL{UID: segments[index+1]}
+	if len(segments) > index+2 {
+		result.Slug = segments[index+2]
+	}
+	base := &url.URL{Scheme: parsedURL.Scheme, User: parsedURL.User, Host: parsedURL.Host}
+	if index > 0 {
+		base.Path = "/" + strings.Join(segments[:index], "/")
+	}
+	result.BaseURL = base.String()
+
+	query := parsedURL.Query()
+	for name, values := range query {
+		if strings.HasPrefix(name, variablePrefix) {
+			result.SetVariable(strings.TrimPrefix(name, variablePrefix), values...)
+		}
+	}
+	if orgID := query.Get("orgId"); len(orgID) > 0 {
+		if result.OrgID, err = strconv.ParseInt(orgID, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid orgId '%s'", orgID)
+		}
+	}
+	if viewPanel := query.Get("viewPanel"); len(viewPanel) > 0 {
+		if result.ViewPanel, err = strconv.ParseInt(viewPanel, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid viewPanel '%s'", viewPanel)
+		}
+	}
+	if _, exist := query["kiosk"]; exist {
+		result.Kiosk = KioskFull
+		if query.Get("kiosk") == string(KioskTV) {
+			result.Kiosk = KioskTV
+		}
+	}
+	result.From = query.Get("from")
+	result.To = query.Get("to")
+	result.Theme = Theme(query.Get("theme"))
+	return result, nil
+}
+
+// SetTimeRange sets an absolute time range
+func (u *URL) SetTimeRange(from time.Time, to time.Time) *URL {
+	u.From = epochMillis(from)
+	u.To = epochMillis(to)
+	return u
+}
+
+// SetVariable sets the values of a template variable. It replaces the values previously set.
+func (u *URL) SetVariable(name string, values ...string) *URL {
+	if u.Variables == nil {
+		u.Variables = make(map[string][]string)
+	}
+	u.Variables[name] = values
+	return u
+}
+
+// Path returns the URL relative to the root of Grafana, without leading slash.
+// It's the format expected to create a short URL.
+func (u *URL) Path() string {
+	path := "d/" + url.PathEscape(u.UID)
+	if len(u.Slug) > 0 {
+		path += "/" + url.PathEscape(u.Slug)
+	}
+	if query := u.query().Encode(); len(query) > 0 {
+		path += "?" + query
+	}
+	return path
+}
+
+// Shorten creates a short link to the dashboard. The base URL is ignored, the link is created on the Grafana of the client.
+func (u *URL) Shorten(client api.ClientInterface) (*types.ShortURL, error) {
+	return client.ShortURLs().Create(u.Path())
+}
+
+func (u *URL) String() string {
+	return strings.TrimSuffix(u.BaseURL, "/") + "/" + u.Path()
+}
+
+func (u *URL) query() url.Values {
+	values := make(url.Values)
+	if u.OrgID > 0 {
+		values.Set("orgId", strconv.FormatInt(u.OrgID, 10))
+	}
+	if len(u.From) > 0 {
+		values.Set("from", u.From)
+	}
+	if len(u.To) > 0 {
+		values.Set("to", u.To)
+	}
+	for name, variableValues := range u.Variables {
+		values[variablePrefix+name] = append([]string{}, variableValues...)
+	}
+	if u.ViewPanel > 0 {
+		values.Set("viewPanel", strconv.FormatInt(u.ViewPanel, 10))
+	}
+	if len(u.Kiosk) > 0 {
+		values.Set("kiosk", string(u.Kiosk))
+	}
+	if len(u.Theme) > 0 {
+		values.Set("theme", string(u.Theme))
+	}
+	return values
+}
+
+// ExploreURL builds the link of the Explore page
+type ExploreURL struct {
+	// BaseURL has the same meaning as in URL
+	BaseURL       string
+	OrgID         int64
+	DatasourceUID string
+	// Queries are the targets to run, in the same format as the targets of a panel
+	Queries []map[string]interface{}
+	// From and To have the same meaning as in URL. The last hour is displayed when they are not set.
+	From string
+	To   string
+}
+
+// NewExploreURLFromPanel returns the Explore URL running the queries of the panel.
+// The datasource of the panel must be set with its UID, a variable can't be resolved here.
+func NewExploreURLFromPanel(panel map[string]interface{}) *ExploreURL {
+	result := &ExploreURL{}
+	if ref, isRef := ParseDatasourceRef(panel["datasource"]); isRef {
+		result.DatasourceUID = ref.UID
+	}
+	result.Queries = Targets(panel)
+	return result
+}
+
+// SetTimeRange sets an absolute time range
+func (e *ExploreURL) SetTimeRange(from time.Time, to time.Time) *ExploreURL {
+	e.From = epochMillis(from)
+	e.To = epochMillis(to)
+	return e
+}
+
+// Path returns the URL relative to the root of Grafana, without leading slash.
+// The state of Explore is encoded in the parameter left, which is still understood by the recent versions of Grafana.
+func (e *ExploreURL) Path() (string, error) {
+	from, to := e.From, e.To
+	if len(from) == 0 {
+		from = "now-1h"
+	}
+	if len(to) == 0 {
+		to = "now"
+	}
+	queries := e.Queries
+	if queries == nil {
+		queries = []map[string]interface{}{}
+	}
+	state, err := json.Marshal(map[string]interface{}{
+		"datasource": e.DatasourceUID,
+		"queries":    queries,
+		"range":      map[string]string{"from": from, "to": to},
+	})
+	if err != nil {
+		return "", err
+	}
+	values := make(url.Values)
+	values.Set("left", string(state))
+	if e.OrgID > 0 {
+		values.Set("orgId", strconv.FormatInt(e.OrgID, 10))
+	}
+	return "explore?" + values.Encode(), nil
+}
+
+// Shorten creates a short link to the Explore page. The base URL is ignored, the link is created on the Grafana of the client.
+func (e *ExploreURL) Shorten(client api.ClientInterface) (*types.ShortURL, error) {
+	path, err := e.Path()
+	if err != nil {
+		return nil, err
+	}
+	return client.ShortURLs().Create(path)
+}
+
+// Build returns the full URL. It fails only if a query can't be encoded in JSON.
+func (e *ExploreURL) Build() (string, error) {
+	path, err := e.Path()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(e.BaseURL, "/") + "/" + path, nil
+}
+
+func epochMillis(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
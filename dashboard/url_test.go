@@ -0,0 +1,133 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL_String(t *testing.T) {
+	testSuites := []struct {
+		title    string
+		url      *URL
+		expected string
+	}{
+		{
+			title:    "relative URL without parameter",
+			url:      NewURL("abcd", "my-dashboard"),
+			expected: "/d/abcd/my-dashboard",
+		},
+		{
+			title: "URL with all parameters",
+			url: &URL{
+				BaseURL:   "https://grafana.example.com/grafana/",
+				UID:       "abcd",
+				Slug:      "my-dashboard",
+				OrgID:     2,
+				From:      "now-6h",
+				To:        "now",
+				Variables: map[string][]string{"job": {"node", "api server"}},
+				ViewPanel: 4,
+				Kiosk:     KioskFull,
+				Theme:     LightTheme,
+			},
+			expected: "https://grafana.example.com/grafana/d/abcd/my-dashboard?from=now-6h&kiosk=1&orgId=2&theme=light&to=now&var-job=node&var-job=api+server&viewPanel=4",
+		},
+		{
+			title:    "absolute time range",
+			url:      NewURL("abcd", "").SetTimeRange(time.Unix(1600000000, 0), time.Unix(1600003600, 0)),
+			expected: "/d/abcd?from=1600000000000&to=1600003600000",
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.url.String())
+		})
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	testSuites := []struct {
+		title         string
+		rawURL        string
+		expected      *URL
+		expectedError bool
+	}{
+		{
+			title:    "URL of a search result",
+			rawURL:   "/d/abcd/my-dashboard",
+			expected: &URL{UID: "abcd", Slug: "my-dashboard"},
+		},
+		{
+			title:  "absolute URL with sub path and parameters",
+			rawURL: "https://grafana.example.com/grafana/d/abcd/my-dashboard?orgId=3&var-job=node&var-job=api&kiosk&viewPanel=2",
+			expected: &URL{
+				BaseURL:   "https://grafana.example.com/grafana",
+				UID:       "abcd",
+				Slug:      "my-dashboard",
+				OrgID:     3,
+				Variables: map[string][]string{"job": {"node", "api"}},
+				ViewPanel: 2,
+				Kiosk:     KioskFull,
+			},
+		},
+		{
+			title:    "dashboard whose UID is d",
+			rawURL:   "https://grafana.example.com/grafana/d/d/my-dashboard",
+			expected: &URL{BaseURL: "https://grafana.example.com/grafana", UID: "d", Slug: "my-dashboard"},
+		},
+		{
+			title:    "dashboard whose UID is d without slug",
+			rawURL:   "/d/d",
+			expected: &URL{UID: "d"},
+		},
+		{
+			title:         "not a dashboard",
+			rawURL:        "/dashboards/f/abcd/my-folder",
+			expectedError: true,
+		},
+	}
+	for _, test := range testSuites {
+		t.Run(test.title, func(t *testing.T) {
+			result, err := ParseURL(test.rawURL)
+			if test.expectedError {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestExploreURL_Build(t *testing.T) {
+	model := newTestDashboard(t, exportTestDashboard)
+	exploreURL := NewExploreURLFromPanel(Panels(model)[0])
+	exploreURL.BaseURL = "https://grafana.example.com"
+	exploreURL.OrgID = 1
+
+	result, err := exploreURL.Build()
+	assert.Nil(t, err)
+	parsedURL, err := url.Parse(result)
+	assert.Nil(t, err)
+	assert.Equal(t, "/explore", parsedURL.Path)
+	assert.Equal(t, "1", parsedURL.Query().Get("orgId"))
+	assert.JSONEq(t,
+		`{"datasource": "prom-uid", "queries": [{"refId": "A", "datasource": {"type": "prometheus", "uid": "prom-uid"}, "expr": "up"}], "range": {"from": "now-1h", "to": "now"}}`,
+		parsedURL.Query().Get("left"))
+}
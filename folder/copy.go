@@ -0,0 +1,107 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package folder
+
+import (
+	"fmt"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/dashboard/clone"
+)
+
+type CopyOptions struct {
+	// ParentUID is the folder in which the copy is created. The copy is created at the root when it's empty.
+	ParentUID string
+	// Title of the copy. When it's empty, the title of the source followed by " (copy)" is used.
+	// The subfolders and the dashboards keep their title.
+	Title string
+	// Message is the commit message of the dashboards copied
+	Message string
+	// Permissions tells what happens to the permissions set explicitly on the dashboards
+	Permissions clone.PermissionMode
+	// DryRun allows to get the report without creating anything
+	DryRun bool
+}
+
+// Copy copies the folder with all its subfolders and all its dashboards. Grafana generates the uid of every copy.
+// When a folder can't be created, its content is not copied but the other folders are.
+// An error is returned only if the content of a folder can't be listed.
+func Copy(client api.ClientInterface, uid string, options CopyOptions) (*Report, error) {
+	source, err := client.Folders().GetByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.ParentUID) > 0 {
+		inside, err := isDescendant(client, options.ParentUID, uid)
+		if err != nil {
+			return nil, err
+		}
+		if inside {
+			return nil, fmt.Errorf("the folder '%s' can't be copied inside itself", uid)
+		}
+	}
+	title := options.Title
+	if len(title) == 0 {
+		title = source.Title + " (copy)"
+	}
+	report := &Report{DryRun: options.DryRun}
+	return report, copyFolder(client, uid, title, options.ParentUID, options, report)
+}
+
+func copyFolder(client api.ClientInterface, sourceUID string, title string, parentUID string, options CopyOptions, report *Report) error {
+	result := &FolderResult{SourceUID: sourceUID, Title: title, ParentUID: parentUID}
+	report.Folders = append(report.Folders, result)
+	if !options.DryRun {
+		created, err := client.Folders().CreateFolder(&types.CreateFolder{Title: title, ParentUID: parentUID})
+		if err != nil {
+			result.Error = err.Error()
+			return nil
+		}
+		result.UID = created.UID
+	}
+
+	hits, err := dashboardsOf(client, sourceUID)
+	if err != nil {
+		return err
+	}
+	for _, hit := range hits {
+		dashboardResult := &DashboardResult{SourceUID: hit.UID, Title: hit.Title, FolderUID: result.UID}
+		report.Dashboards = append(report.Dashboards, dashboardResult)
+		if options.DryRun {
+			continue
+		}
+		copied, err := clone.Dashboard(client, hit.UID, clone.Target{FolderUID: result.UID}, clone.Options{
+			Message:     options.Message,
+			Permissions: options.Permissions,
+		})
+		if copied != nil && copied.Dashboard != nil {
+			dashboardResult.UID = copied.Dashboard.UID
+		}
+		if err != nil {
+			dashboardResult.Error = err.Error()
+		}
+	}
+
+	children, err := client.Folders().GetChildren(sourceUID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := copyFolder(client, child.UID, child.Title, result.UID, options, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
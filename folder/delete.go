@@ -0,0 +1,74 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package folder
+
+import (
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+)
+
+type DeleteOptions struct {
+	// DryRun allows to get the preview of everything that would be deleted without deleting anything
+	DryRun bool
+}
+
+// Delete deletes the folder. Grafana deletes with it its subfolders, its dashboards and its alert rules,
+// so the report lists all of them before the deletion happens.
+// The library panels of the folder can prevent the deletion when they are still used by a dashboard.
+func Delete(client api.ClientInterface, uid string, options DeleteOptions) (*Report, error) {
+	root, err := client.Folders().GetByUID(uid)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{DryRun: options.DryRun}
+	if err := listContent(client, uid, root.Title, root.ParentUID, report); err != nil {
+		return nil, err
+	}
+	report.Counts, err = client.Folders().GetDescendantCounts(uid)
+	if err != nil {
+		if !grafanahttp.IsNotFound(err) {
+			return nil, err
+		}
+		// the counts are provided only by Grafana >= 10
+		report.Counts = nil
+	}
+	if options.DryRun {
+		return report, nil
+	}
+	if err := client.Folders().Delete(uid); err != nil {
+		report.Folders[0].Error = err.Error()
+	}
+	return report, nil
+}
+
+func listContent(client api.ClientInterface, uid string, title string, parentUID string, report *Report) error {
+	report.Folders = append(report.Folders, &FolderResult{UID: uid, Title: title, ParentUID: parentUID})
+	hits, err := dashboardsOf(client, uid)
+	if err != nil {
+		return err
+	}
+	for _, hit := range hits {
+		report.Dashboards = append(report.Dashboards, &DashboardResult{UID: hit.UID, Title: hit.Title, FolderUID: uid})
+	}
+	children, err := client.Folders().GetChildren(uid)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := listContent(client, child.UID, child.Title, uid, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package folder provides recursive operations on the folders: copy a folder with its subfolders and its dashboards,
+// delete a folder with a preview of everything deleted, and move the dashboards matching a search into a folder.
+// Every operation produces a report describing what has been done, or what would be done in dry run.
+package folder
+
+import (
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// FolderResult describes what happened to a folder
+type FolderResult struct {
+	// SourceUID is the folder copied. It's only set by Copy.
+	SourceUID string `json:"sourceUid,omitempty"`
+	// UID is empty when the folder would be created in dry run
+	UID   string `json:"uid,omitempty"`
+	Title string `json:"title"`
+	// ParentUID is the parent of the folder. Like UID, it's empty for a subfolder copied in dry run.
+	ParentUID string `json:"parentUid,omitempty"`
+	// Error is set when the operation failed for this folder
+	Error string `json:"error,omitempty"`
+}
+
+// DashboardResult describes what happened to a dashboard
+type DashboardResult struct {
+	// SourceUID is the dashboard copied. It's only set by Copy.
+	SourceUID string `json:"sourceUid,omitempty"`
+	// UID is empty when the dashboard would be created in dry run
+	UID   string `json:"uid,omitempty"`
+	Title string `json:"title"`
+	// FolderUID is the folder of the dashboard once the operation is done, or the folder deleted with it
+	FolderUID string `json:"folderUid,omitempty"`
+	// PreviousFolderUID is the folder of the dashboard before it's moved. It's only set by MoveDashboards.
+	PreviousFolderUID string `json:"previousFolderUid,omitempty"`
+	// Error is set when the operation failed for this dashboard
+	Error string `json:"error,omitempty"`
+}
+
+type Report struct {
+	DryRun     bool               `json:"dryRun"`
+	Folders    []*FolderResult    `json:"folders,omitempty"`
+	Dashboards []*DashboardResult `json:"dashboards,omitempty"`
+	// Counts are the resources stored in the folder deleted, including the library panels and the alert rules.
+	// It's only set by Delete when the version of Grafana provides it.
+	Counts *types.FolderDescendantCounts `json:"counts,omitempty"`
+}
+
+// Failed returns true if the operation failed for at least one folder or one dashboard
+func (r *Report) Failed() bool {
+	for _, f := range r.Folders {
+		if len(f.Error) > 0 {
+			return true
+		}
+	}
+	for _, d := range r.Dashboards {
+		if len(d.Error) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// dashboardsOf returns the dashboards stored directly in the folder
+func dashboardsOf(client api.ClientInterface, folderUID string) ([]*types.SearchResult, error) {
+	return client.Search().QueryAll(api.QueryParameterSearch{
+		SearchType: types.SearchDashboardType,
+		FolderUIDs: []string{folderUID},
+	})
+}
+
+// isDescendant returns true if the folder uid is the folder ancestorUID or one of its subfolders
+func isDescendant(client api.ClientInterface, uid string, ancestorUID string) (bool, error) {
+	if uid == ancestorUID {
+		return true, nil
+	}
+	parents, err := client.Folders().GetParents(uid)
+	if err != nil {
+		return false, err
+	}
+	for _, parent := range parents {
+		if parent.UID == ancestorUID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
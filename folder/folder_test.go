@@ -0,0 +1,141 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package folder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/nexucis/grafana-go-client/grafanahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+// testGrafana records the dashboards saved by the fake Grafana
+type testGrafana struct {
+	saved []*types.SaveDashboard
+}
+
+// newTestClient returns a client of a fake Grafana having the folders root > child,
+// the dashboard a in root, the dashboard b in child and the dashboard c in the General folder.
+func newTestClient(t *testing.T) (*testGrafana, api.ClientInterface, func()) {
+	fake := &testGrafana{}
+	dashboards := []*types.SearchResult{
+		{UID: "a", Title: "A", FolderUID: "root"},
+		{UID: "b", Title: "B", FolderUID: "child"},
+		{UID: "c", Title: "C"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+		switch r.URL.Path {
+		case "/api/frontend/settings":
+			response = map[string]interface{}{"featureToggles": map[string]bool{"nestedFolders": true}}
+		case "/api/folders/root":
+			response = &types.Folder{UID: "root", Title: "Root"}
+		case "/api/folders/root/counts":
+			response = &types.FolderDescendantCounts{Folders: 1, Dashboards: 2, AlertRules: 3}
+		case "/api/folders":
+			response = []*types.SimpleFolder{}
+			if r.URL.Query().Get("parentUid") == "root" {
+				response = []*types.SimpleFolder{{UID: "child", Title: "Child"}}
+			}
+		case "/api/dashboards/uid/a", "/api/dashboards/uid/c":
+			for _, d := range dashboards {
+				if r.URL.Path == "/api/dashboards/uid/"+d.UID {
+					response = &types.DashboardWithMeta{
+						Meta:      types.DashboardMeta{FolderUID: d.FolderUID},
+						Dashboard: types.DashboardModel{"uid": d.UID, "title": d.Title, "version": 1},
+					}
+				}
+			}
+		case "/api/dashboards/db":
+			saved := &types.SaveDashboard{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(saved))
+			fake.saved = append(fake.saved, saved)
+			response = &types.SimpleDashboard{UID: saved.Dashboard.UID(), Version: 2, Status: "success"}
+		case "/api/search":
+			result := []*types.SearchResult{}
+			folderUID := r.URL.Query().Get("folderUIDs")
+			for _, d := range dashboards {
+				if len(folderUID) == 0 || d.FolderUID == folderUID {
+					result = append(result, d)
+				}
+			}
+			response = result
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	rest, err := grafanahttp.NewWithURL(server.URL)
+	assert.Nil(t, err)
+	return fake, api.NewWithClient(rest), server.Close
+}
+
+func TestDelete_DryRun(t *testing.T) {
+	_, client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	report, err := Delete(client, "root", DeleteOptions{DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, &Report{
+		DryRun: true,
+		Folders: []*FolderResult{
+			{UID: "root", Title: "Root"},
+			{UID: "child", Title: "Child", ParentUID: "root"},
+		},
+		Dashboards: []*DashboardResult{
+			{UID: "a", Title: "A", FolderUID: "root"},
+			{UID: "b", Title: "B", FolderUID: "child"},
+		},
+		Counts: &types.FolderDescendantCounts{Folders: 1, Dashboards: 2, AlertRules: 3},
+	}, report)
+	assert.False(t, report.Failed())
+}
+
+func TestMoveDashboards_DryRun(t *testing.T) {
+	_, client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	report, err := MoveDashboards(client, api.QueryParameterSearch{}, "child", MoveOptions{DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []*DashboardResult{
+		{UID: "a", Title: "A", FolderUID: "child", PreviousFolderUID: "root"},
+		{UID: "c", Title: "C", FolderUID: "child"},
+	}, report.Dashboards)
+}
+
+func TestMoveDashboards(t *testing.T) {
+	fake, client, closeServer := newTestClient(t)
+	defer closeServer()
+
+	report, err := MoveDashboards(client, api.QueryParameterSearch{}, "child", MoveOptions{})
+	assert.Nil(t, err)
+	assert.False(t, report.Failed())
+	assert.Len(t, report.Dashboards, 2)
+	if assert.Len(t, fake.saved, 2) {
+		for _, saved := range fake.saved {
+			assert.Equal(t, "child", saved.FolderUID)
+			assert.Equal(t, defaultMoveMessage, saved.Message)
+			// the version fetched is sent so a concurrent modification is detected
+			assert.Equal(t, float64(1), saved.Dashboard["version"])
+			assert.False(t, saved.Overwrite)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package folder
+
+import (
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+const defaultMoveMessage = "move dashboard"
+
+type MoveOptions struct {
+	// Message is the commit message used when a dashboard is saved. A default message is used when it's empty.
+	Message string
+	// DryRun allows to get the report without moving any dashboard
+	DryRun bool
+}
+
+// MoveDashboards moves every dashboard matching the query into the folder targetUID.
+// The General folder is the target when targetUID is empty. The dashboards already in the target are skipped.
+// Each dashboard is moved with DashboardInterface.Move, so a concurrent modification is never overwritten.
+// An error is returned only if the search fails, the errors related to a dashboard are set in its result.
+func MoveDashboards(client api.ClientInterface, query api.QueryParameterSearch, targetUID string, options MoveOptions) (*Report, error) {
+	message := options.Message
+	if len(message) == 0 {
+		message = defaultMoveMessage
+	}
+	query.SearchType = types.SearchDashboardType
+	hits, err := client.Search().QueryAll(query)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: options.DryRun}
+	for _, hit := range hits {
+		if hit.FolderUID == targetUID {
+			continue
+		}
+		result := &DashboardResult{UID: hit.UID, Title: hit.Title, FolderUID: targetUID, PreviousFolderUID: hit.FolderUID}
+		report.Dashboards = append(report.Dashboards, result)
+		if options.DryRun {
+			continue
+		}
+		if _, err := client.Dashboards().Move(hit.UID, targetUID, message); err != nil {
+			result.Error = err.Error()
+		}
+	}
+	return report, nil
+}
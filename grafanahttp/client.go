@@ -19,10 +19,15 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
-const connectionTimeout = 30 * time.Second
+const (
+	connectionTimeout = 30 * time.Second
+	// OrgIDHeader allows to run a request in another organisation than the current one of the user
+	OrgIDHeader = "X-Grafana-Org-Id"
+)
 
 // RestConfigClient defines all parameter that can be set to customize the RESTClient
 type RestConfigClient struct {
@@ -78,6 +83,16 @@ type RESTClient struct {
 	BaseURL *url.URL
 	// Set specific behavior of the client.  If not set http.DefaultClient will be used.
 	Client *http.Client
+	// OrgID is the organisation in which every request is run. When it's not set, the current organisation of the user is used.
+	// The user must be a member of the organisation.
+	OrgID int64
+}
+
+// WithOrgID returns a copy of the client running every request in the given organisation
+func (c *RESTClient) WithOrgID(orgID int64) *RESTClient {
+	result := *c
+	result.OrgID = orgID
+	return &result
 }
 
 // Get begins a GET request. Short for c.newRequest("GET")
@@ -106,5 +121,9 @@ func (c *RESTClient) Delete(pathPrefix string) *Request {
 }
 
 func (c *RESTClient) newRequest(method string, pathPrefix string) *Request {
-	return NewRequest(c.Client, method, c.BaseURL, pathPrefix, c.Token)
+	request := NewRequest(c.Client, method, c.BaseURL, pathPrefix, c.Token)
+	if c.OrgID > 0 {
+		request.SetHeader(OrgIDHeader, strconv.FormatInt(c.OrgID, 10))
+	}
+	return request
 }
@@ -0,0 +1,30 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grafanahttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRESTClient_WithOrgID(t *testing.T) {
+	client, err := NewWithURL("http://localhost:3000")
+	assert.Nil(t, err)
+	orgClient := client.WithOrgID(3)
+
+	assert.Equal(t, int64(0), client.OrgID)
+	assert.Equal(t, "", client.Get("/api/search").header.Get(OrgIDHeader))
+	assert.Equal(t, "3", orgClient.Get("/api/search").header.Get(OrgIDHeader))
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -45,8 +46,9 @@ type Request struct {
 	queryParam url.Values
 	pathParam  map[string]string
 
-	body io.Reader
-	err  error
+	header http.Header
+	body   io.Reader
+	err    error
 }
 
 func NewRequest(client *http.Client, method string, baseURL *url.URL, pathPrefix string, token string) *Request {
@@ -67,6 +69,15 @@ func (r *Request) SetPathParam(pathName string, value string) *Request {
 	return r
 }
 
+// SetHeader sets a header of the request. It overrides the default headers set by the client like Accept.
+func (r *Request) SetHeader(name string, value string) *Request {
+	if r.header == nil {
+		r.header = make(http.Header)
+	}
+	r.header.Set(name, value)
+	return r
+}
+
 func (r *Request) SetSubPath(subPath string) *Request {
 	r.subpath = subPath
 	return r
@@ -111,8 +122,60 @@ func (r *Request) AddQueryParam(queryName string, value string) *Request {
 }
 
 func (r *Request) Do() *Response {
+	resp, errResponse := r.send()
+	if errResponse != nil {
+		return errResponse
+	}
+
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	// Deserialize the json response
+	if resp.Body != nil {
+		data, err := ioutil.ReadAll(resp.Body)
+		return &Response{body: data, err: err, statusCode: resp.StatusCode, header: resp.Header}
+	}
+
+	return &Response{statusCode: resp.StatusCode, header: resp.Header}
+}
+
+// Stream copies the body of a successful response into the writer instead of keeping it in memory.
+// When expectedContentType is not empty, the body is copied only if the response has this content type.
+// The body of an error response is kept in memory like Do does it, to be able to build the error.
+func (r *Request) Stream(w io.Writer, expectedContentType string) *Response {
+	resp, errResponse := r.send()
+	if errResponse != nil {
+		return errResponse
+	}
+
+	defer func() {
+		resp.Body.Close()
+	}()
+
+	response := &Response{statusCode: resp.StatusCode, header: resp.Header}
+	if resp.Body == nil {
+		return response
+	}
+	if !isSuccess(resp.StatusCode) {
+		response.body, response.err = ioutil.ReadAll(resp.Body)
+		return response
+	}
+	if len(expectedContentType) > 0 {
+		contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil || contentType != expectedContentType {
+			response.err = fmt.Errorf("unexpected content type '%s', expected '%s'", resp.Header.Get("Content-Type"), expectedContentType)
+			return response
+		}
+	}
+	_, response.err = io.Copy(w, resp.Body)
+	return response
+}
+
+// send executes the request. When it fails, the response to return is built and the http response is nil.
+func (r *Request) send() (*http.Response, *Response) {
 	if r.err != nil {
-		return &Response{err: r.err}
+		return nil, &Response{err: r.err}
 	}
 
 	httpClient := r.client
@@ -123,7 +186,7 @@ func (r *Request) Do() *Response {
 	httpRequest, err := r.prepareRequest()
 
 	if err != nil {
-		return &Response{err: err}
+		return nil, &Response{err: err}
 	}
 
 	resp, err := httpClient.Do(httpRequest)
@@ -133,25 +196,14 @@ func (r *Request) Do() *Response {
 		if ctx != nil {
 			select {
 			case <-ctx.Done():
-				return &Response{err: ctx.Err()}
+				return nil, &Response{err: ctx.Err()}
 			default:
 			}
 		}
 
-		return &Response{err: err}
-	}
-
-	defer func() {
-		resp.Body.Close()
-	}()
-
-	// Deserialize the json response
-	if resp.Body != nil {
-		data, err := ioutil.ReadAll(resp.Body)
-		return &Response{body: data, err: err, statusCode: resp.StatusCode}
+		return nil, &Response{err: err}
 	}
-
-	return &Response{statusCode: resp.StatusCode}
+	return resp, nil
 }
 
 func (r *Request) prepareRequest() (*http.Request, error) {
@@ -183,6 +235,10 @@ func (r *Request) prepareRequest() (*http.Request, error) {
 		httpRequest.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	}
 
+	for name, values := range r.header {
+		httpRequest.Header[name] = values
+	}
+
 	return httpRequest, nil
 }
 
@@ -245,7 +301,9 @@ type GrafanaErrorResponse struct {
 }
 
 type RequestError struct {
-	Message    string
+	Message string
+	// Status is a short code sent by Grafana for some errors, like "version-mismatch" or "name-exists"
+	Status     string
 	StatusCode int
 	Err        error
 }
@@ -259,6 +317,9 @@ func (re *RequestError) Error() string {
 	if len(re.Message) > 0 {
 		err = err + " Message: " + re.Message
 	}
+	if len(re.Status) > 0 {
+		err = err + " Status: " + re.Status
+	}
 
 	if re.StatusCode > 0 {
 		err = err + " StatusCode: " + strconv.Itoa(re.StatusCode)
@@ -267,17 +328,33 @@ func (re *RequestError) Error() string {
 	return err
 }
 
+// IsNotFound returns true if the error has been sent by Grafana because the resource, or the API itself, doesn't exist
+func IsNotFound(err error) bool {
+	requestErr, isRequestErr := err.(*RequestError)
+	return isRequestErr && requestErr.StatusCode == http.StatusNotFound
+}
+
 type Response struct {
 	body       []byte
 	err        error
 	statusCode int
+	header     http.Header
+}
+
+// Header returns the headers of the response. It's nil if the request couldn't be sent.
+func (r *Response) Header() http.Header {
+	return r.header
+}
+
+func isSuccess(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode <= http.StatusPartialContent
 }
 
 func (r *Response) Error() error {
 
 	e := &RequestError{Err: r.err}
 	// check code result
-	if r.statusCode < http.StatusOK || r.statusCode > http.StatusPartialContent {
+	if !isSuccess(r.statusCode) {
 		// check error message contains in the body
 		if r.body != nil {
 			g := &GrafanaErrorResponse{}
@@ -306,6 +383,7 @@ func (r *Response) Error() error {
 
 			}
 			e.Message = g.Message
+			e.Status = g.Status
 		}
 		e.StatusCode = r.statusCode
 	}
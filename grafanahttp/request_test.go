@@ -14,7 +14,10 @@
 package grafanahttp
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -120,6 +123,16 @@ func TestRequest_SetPathParam(t *testing.T) {
 	}
 }
 
+func TestRequest_SetHeader(t *testing.T) {
+	request := &Request{method: "GET", baseURL: &url.URL{Scheme: "http", Host: "localhost:3000"}, pathPrefix: "/api/search"}
+	request.SetHeader("X-Grafana-Org-Id", "2").SetHeader("Accept", "image/png")
+
+	httpRequest, err := request.prepareRequest()
+	assert.Nil(t, err)
+	assert.Equal(t, "2", httpRequest.Header.Get("X-Grafana-Org-Id"))
+	assert.Equal(t, "image/png", httpRequest.Header.Get("Accept"))
+}
+
 func TestRequest_SetSubPath(t *testing.T) {
 	subPath := "/:id/metrics"
 	request := Request{}
@@ -211,3 +224,63 @@ func TestRequest_URL(t *testing.T) {
 		assert.Equal(t, testSuite.expectedURL, result, info)
 	}
 }
+
+func TestRequest_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png content")) // nolint: errcheck
+		case "/login":
+			w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+			w.Write([]byte("<html></html>")) // nolint: errcheck
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`)) // nolint: errcheck
+		}
+	}))
+	defer server.Close()
+
+	testSuites := []struct {
+		title          string
+		path           string
+		expectedBody   string
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			title:          "body copied",
+			path:           "/image",
+			expectedBody:   "png content",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			title:          "unexpected content type",
+			path:           "/login",
+			expectedStatus: http.StatusOK,
+			expectedError:  true,
+		},
+		{
+			title:          "error response",
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  true,
+		},
+	}
+	for _, testSuite := range testSuites {
+		t.Run(testSuite.title, func(t *testing.T) {
+			client, err := NewWithURL(server.URL)
+			assert.Nil(t, err)
+			buffer := &bytes.Buffer{}
+			response := client.Get(testSuite.path).Stream(buffer, "image/png")
+			assert.Equal(t, testSuite.expectedBody, buffer.String())
+			assert.Equal(t, testSuite.expectedStatus, response.statusCode)
+			if testSuite.expectedError {
+				assert.NotNil(t, response.Error())
+			} else {
+				assert.Nil(t, response.Error())
+			}
+		})
+	}
+}
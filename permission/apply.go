@@ -0,0 +1,115 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"fmt"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+type Options struct {
+	// KeepUnlisted keeps the permissions not listed in the set instead of removing them,
+	// which allows to add a permission without knowing the others.
+	KeepUnlisted bool
+	// DryRun allows to get the report without modifying the ACL
+	DryRun bool
+}
+
+// Report describes the changes of the ACL of a folder or a dashboard
+type Report struct {
+	UID string `json:"uid"`
+	Change
+	// Applied is true when the ACL has been updated. It's false when there is no change or in dry run.
+	Applied bool `json:"applied"`
+}
+
+// Resolve returns a copy of the set in which the users and the teams given by name are identified by their id
+func Resolve(client api.ClientInterface, set Set) (Set, error) {
+	result := make(Set, 0, len(set))
+	for _, grant := range set {
+		subject := grant.Subject
+		if subject.UserID == 0 && len(subject.UserLogin) > 0 {
+			user, err := client.Users().GetByLoginOrEmail(subject.UserLogin)
+			if err != nil {
+				return nil, fmt.Errorf("unable to find the user '%s': %s", subject.UserLogin, err)
+			}
+			subject.UserID = user.ID
+		}
+		if subject.TeamID == 0 && len(subject.TeamName) > 0 {
+			teams, err := client.Teams().Get(api.QueryParameterTeams{Name: subject.TeamName})
+			if err != nil {
+				return nil, err
+			}
+			if len(teams.Teams) == 0 {
+				return nil, fmt.Errorf("unable to find the team '%s'", subject.TeamName)
+			}
+			subject.TeamID = teams.Teams[0].ID
+		}
+		result = append(result, &Grant{Subject: subject, Permission: grant.Permission})
+	}
+	return result, nil
+}
+
+// ApplyToFolder updates the ACL of the folder so it matches the set. The ACL is updated only when it changes.
+func ApplyToFolder(client api.ClientInterface, uid string, set Set, options Options) (*Report, error) {
+	folders := client.Folders()
+	return applyACL(client, uid, set, options,
+		func() ([]*types.FolderOrDashboardPermission, error) {
+			return folders.GetPermissions(uid)
+		},
+		func(items []*types.DashboardACLUpdateItem) error {
+			return folders.UpdatePermissions(uid, items)
+		})
+}
+
+// ApplyToDashboard updates the ACL of the dashboard so it matches the set. The ACL is updated only when it changes.
+// The permissions inherited from the folder of the dashboard are not taken into account.
+func ApplyToDashboard(client api.ClientInterface, uid string, set Set, options Options) (*Report, error) {
+	dashboards := client.Dashboards()
+	return applyACL(client, uid, set, options,
+		func() ([]*types.FolderOrDashboardPermission, error) {
+			return dashboards.GetPermissionsByUID(uid)
+		},
+		func(items []*types.DashboardACLUpdateItem) error {
+			return dashboards.UpdatePermissionsByUID(uid, items)
+		})
+}
+
+func applyACL(client api.ClientInterface, uid string, set Set, options Options,
+	get func() ([]*types.FolderOrDashboardPermission, error),
+	update func([]*types.DashboardACLUpdateItem) error) (*Report, error) {
+	resolved, err := Resolve(client, set)
+	if err != nil {
+		return nil, err
+	}
+	current, err := get()
+	if err != nil {
+		return nil, err
+	}
+	change, err := Diff(current, resolved, options.KeepUnlisted)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{UID: uid, Change: *change}
+	if change.IsEmpty() || options.DryRun {
+		return report, nil
+	}
+	if err := update(apply(current, change)); err != nil {
+		return nil, err
+	}
+	report.Applied = true
+	return report, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"sort"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+// teamsPerPage is the page size used to list the teams
+const teamsPerPage = 1000
+
+// Member is a user of the organisation as seen by the permission checks
+type Member struct {
+	ID      int64          `json:"id"`
+	Login   string         `json:"login"`
+	Email   string         `json:"email,omitempty"`
+	OrgRole types.RoleType `json:"orgRole"`
+	// IsGrafanaAdmin is only set by LoadMember since it requires a request per user
+	IsGrafanaAdmin bool    `json:"isGrafanaAdmin,omitempty"`
+	TeamIDs        []int64 `json:"teamIds,omitempty"`
+}
+
+// InTeam returns true if the user is a member of the team
+func (u *Member) InTeam(teamID int64) bool {
+	for _, id := range u.TeamIDs {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+// Directory holds the users of the organisation with their role and their teams
+type Directory struct {
+	Members map[int64]*Member
+	Teams   map[int64]*types.Team
+}
+
+// LoadDirectory gathers the users of the current organisation, their role and the members of every team
+func LoadDirectory(client api.ClientInterface) (*Directory, error) {
+	orgUsers, err := client.CurrentOrganisation().GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	directory := &Directory{
+		Members: make(map[int64]*Member, len(orgUsers)),
+		Teams:   make(map[int64]*types.Team),
+	}
+	for _, orgUser := range orgUsers {
+		directory.Members[orgUser.UserID] = &Member{
+			ID:      orgUser.UserID,
+			Login:   orgUser.Login,
+			Email:   orgUser.Email,
+			OrgRole: types.RoleType(orgUser.Role),
+		}
+	}
+
+	query := api.QueryParameterTeams{PerPage: teamsPerPage}
+	for page := int64(1); ; page++ {
+		query.Page = page
+		result, err := client.Teams().Get(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range result.Teams {
+			directory.Teams[team.ID] = team
+		}
+		if int64(len(result.Teams)) < query.PerPage || int64(len(directory.Teams)) >= result.TotalCount {
+			break
+		}
+	}
+	for teamID := range directory.Teams {
+		members, err := client.Teams().GetMembers(teamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if user, exist := directory.Members[member.UserID]; exist {
+				user.TeamIDs = append(user.TeamIDs, teamID)
+			}
+		}
+	}
+	for _, user := range directory.Members {
+		sort.Slice(user.TeamIDs, func(i, j int) bool { return user.TeamIDs[i] < user.TeamIDs[j] })
+	}
+	return directory, nil
+}
+
+// SortedMembers returns the members sorted by login
+func (d *Directory) SortedMembers() []*Member {
+	result := make([]*Member, 0, len(d.Members))
+	for _, user := range d.Members {
+		result = append(result, user)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Login < result[j].Login })
+	return result
+}
@@ -0,0 +1,174 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"fmt"
+
+	"github.com/nexucis/grafana-go-client/api"
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+var roleLevels = map[types.RoleType]int{
+	types.RoleViewer: 1,
+	types.RoleEditor: 2,
+	types.RoleAdmin:  3,
+}
+
+// Evaluation is the effective permission of a user on a dashboard, with the reasons of it
+type Evaluation struct {
+	// Permission is 0 when the user has no access to the dashboard
+	Permission types.PermissionType `json:"permission"`
+	// Grant is the entry of the ACL giving the permission.
+	// It's nil when the permission comes from the role Admin of the user or when the user has no access.
+	Grant *types.FolderOrDashboardPermission `json:"grant,omitempty"`
+	// Explanation describes every rule considered, in the order they are evaluated
+	Explanation []string `json:"explanation"`
+}
+
+// Allows returns true if the effective permission includes the one given, Edit including View for instance
+func (e *Evaluation) Allows(permission types.PermissionType) bool {
+	return e.Permission >= permission
+}
+
+// Evaluate computes the effective permission of the user from the ACL of a dashboard, including the entries
+// inherited from its folder, as Grafana does it with the legacy permissions:
+//
+//   - the role Admin of the organisation gives the permission Admin on every dashboard
+//   - otherwise, the highest permission of the entries matching the user, one of its teams or its role applies
+//   - an entry for a role applies to the users having this role or a higher one, Viewer applying to the editors too
+//
+// Being a Grafana server admin doesn't give any permission on the dashboards of an organisation.
+func Evaluate(member *Member, acl []*types.FolderOrDashboardPermission) *Evaluation {
+	evaluation := &Evaluation{}
+	explain := func(format string, args ...interface{}) {
+		evaluation.Explanation = append(evaluation.Explanation, fmt.Sprintf(format, args...))
+	}
+
+	if member.IsGrafanaAdmin {
+		explain("%s is a Grafana server admin, which doesn't give any permission on the dashboards by itself", member.Login)
+	}
+	if len(member.OrgRole) == 0 {
+		explain("%s is not a member of the organisation", member.Login)
+		return evaluation
+	}
+	if member.OrgRole == types.RoleAdmin {
+		evaluation.Permission = types.PermissionAdmin
+		explain("%s has the role Admin in the organisation, which gives the permission Admin on every dashboard", member.Login)
+		return evaluation
+	}
+	explain("%s has the role %s in the organisation", member.Login, member.OrgRole)
+
+	for _, entry := range acl {
+		applies, reason := matches(member, entry)
+		explain("%s: %s", describe(entry), reason)
+		if applies && entry.Permission > evaluation.Permission {
+			evaluation.Permission = entry.Permission
+			evaluation.Grant = entry
+		}
+	}
+	if evaluation.Grant == nil {
+		explain("no permission applies to %s, so the dashboard is not accessible", member.Login)
+	} else {
+		explain("the effective permission is %s, given by %s", PermissionName(evaluation.Permission), describe(evaluation.Grant))
+	}
+	return evaluation
+}
+
+// Applies returns true if the entry of the ACL applies to the user, directly or through one of its teams or its role.
+// The role Admin of the organisation is not taken into account, see Evaluate.
+func Applies(member *Member, entry *types.FolderOrDashboardPermission) bool {
+	applies, _ := matches(member, entry)
+	return applies
+}
+
+// matches tells whether the entry of the ACL applies to the user, and why
+func matches(member *Member, entry *types.FolderOrDashboardPermission) (bool, string) {
+	switch {
+	case entry.UserID > 0:
+		if entry.UserID == member.ID {
+			return true, "applies to the user itself"
+		}
+		return false, "doesn't apply, it's another user"
+	case entry.TeamID > 0:
+		if member.InTeam(entry.TeamID) {
+			return true, "applies since the user is a member of the team"
+		}
+		return false, "doesn't apply, the user is not a member of the team"
+	case entry.Role != nil:
+		if roleLevels[member.OrgRole] >= roleLevels[*entry.Role] {
+			return true, fmt.Sprintf("applies since the role %s includes the role %s", member.OrgRole, *entry.Role)
+		}
+		return false, fmt.Sprintf("doesn't apply, the role %s doesn't include the role %s", member.OrgRole, *entry.Role)
+	}
+	return false, "doesn't apply, the entry has no subject"
+}
+
+// describe returns a readable description of an entry of an ACL
+func describe(entry *types.FolderOrDashboardPermission) string {
+	var subject string
+	switch {
+	case entry.UserID > 0:
+		subject = "user " + entry.UserLogin
+	case entry.TeamID > 0:
+		subject = "team " + entry.Team
+	case entry.Role != nil:
+		subject = "role " + string(*entry.Role)
+	}
+	origin := "dashboard"
+	if entry.Inherited || entry.IsFolder {
+		origin = "folder"
+	}
+	if len(entry.Title) > 0 {
+		origin = fmt.Sprintf("%s '%s'", origin, entry.Title)
+	}
+	if entry.Inherited {
+		origin = "inherited from the " + origin
+	} else {
+		origin = "set on the " + origin
+	}
+	return fmt.Sprintf("%s %s (%s)", PermissionName(entry.Permission), subject, origin)
+}
+
+// LoadMember gathers the role, the teams and the admin flag of the user in the current organisation
+func LoadMember(client api.ClientInterface, userID int64) (*Member, error) {
+	profile, err := client.Users().GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	directory, err := LoadDirectory(client)
+	if err != nil {
+		return nil, err
+	}
+	member, exist := directory.Members[userID]
+	if !exist {
+		// the user doesn't belong to the organisation, so it has no role
+		member = &Member{ID: userID, Login: profile.Login, Email: profile.Email}
+	}
+	member.IsGrafanaAdmin = profile.IsGrafanaAdmin
+	return member, nil
+}
+
+// EvaluateDashboard gathers everything needed and computes the effective permission of the user on the dashboard
+func EvaluateDashboard(client api.ClientInterface, userID int64, dashboardUID string) (*Evaluation, error) {
+	member, err := LoadMember(client, userID)
+	if err != nil {
+		return nil, err
+	}
+	acl, err := client.Dashboards().GetPermissionsByUID(dashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(member, acl), nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	acl := []*types.FolderOrDashboardPermission{
+		{Inherited: true, Title: "Ops", Role: rolePtr(types.RoleViewer), Permission: types.PermissionView},
+		{Inherited: true, Title: "Ops", TeamID: 3, Team: "Backend", Permission: types.PermissionEdit},
+		{Title: "API", UserID: 9, UserLogin: "bob", Permission: types.PermissionAdmin},
+	}
+
+	testSuites := []struct {
+		title      string
+		member     *Member
+		permission types.PermissionType
+		grant      *types.FolderOrDashboardPermission
+	}{
+		{
+			title:      "viewer",
+			member:     &Member{ID: 1, Login: "alice", OrgRole: types.RoleViewer},
+			permission: types.PermissionView,
+			grant:      acl[0],
+		},
+		{
+			title:      "editor member of a team",
+			member:     &Member{ID: 1, Login: "alice", OrgRole: types.RoleEditor, TeamIDs: []int64{2, 3}},
+			permission: types.PermissionEdit,
+			grant:      acl[1],
+		},
+		{
+			title:      "user with an explicit permission",
+			member:     &Member{ID: 9, Login: "bob", OrgRole: types.RoleViewer, TeamIDs: []int64{3}},
+			permission: types.PermissionAdmin,
+			grant:      acl[2],
+		},
+		{
+			title:      "org admin",
+			member:     &Member{ID: 4, Login: "carol", OrgRole: types.RoleAdmin},
+			permission: types.PermissionAdmin,
+		},
+		{
+			title:  "server admin outside of the organisation",
+			member: &Member{ID: 5, Login: "root", IsGrafanaAdmin: true},
+		},
+	}
+	for _, test := range testSuites {
+		evaluation := Evaluate(test.member, acl)
+		assert.Equal(t, test.permission, evaluation.Permission, test.title)
+		assert.Equal(t, test.grant, evaluation.Grant, test.title)
+		assert.NotEmpty(t, evaluation.Explanation, test.title)
+	}
+
+	evaluation := Evaluate(&Member{ID: 1, Login: "alice", OrgRole: types.RoleEditor, TeamIDs: []int64{3}}, acl)
+	assert.True(t, evaluation.Allows(types.PermissionView))
+	assert.False(t, evaluation.Allows(types.PermissionAdmin))
+	assert.Equal(t, []string{
+		"alice has the role Editor in the organisation",
+		"View role Viewer (inherited from the folder 'Ops'): applies since the role Editor includes the role Viewer",
+		"Edit team Backend (inherited from the folder 'Ops'): applies since the user is a member of the team",
+		"Admin user bob (set on the dashboard 'API'): doesn't apply, it's another user",
+		"the effective permission is Edit, given by Edit team Backend (inherited from the folder 'Ops')",
+	}, evaluation.Explanation)
+}
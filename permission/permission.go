@@ -0,0 +1,308 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package permission manages the permissions of the folders and the dashboards declaratively.
+// A Set describes the permissions wanted, it's compared with the current ACL and only the differences are applied.
+package permission
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+)
+
+var permissionNames = map[types.PermissionType]string{
+	types.PermissionView:  "View",
+	types.PermissionEdit:  "Edit",
+	types.PermissionAdmin: "Admin",
+}
+
+// PermissionName returns the name of the permission as displayed by Grafana, like View
+func PermissionName(permission types.PermissionType) string {
+	if name, exist := permissionNames[permission]; exist {
+		return name
+	}
+	return strconv.Itoa(int(permission))
+}
+
+// ParsePermission converts the name of a permission (View, Edit or Admin, case-insensitive)
+func ParsePermission(name string) (types.PermissionType, error) {
+	for permission, permissionName := range permissionNames {
+		if strings.EqualFold(permissionName, name) {
+			return permission, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown permission '%s', it must be View, Edit or Admin", name)
+}
+
+// Subject is who a permission is granted to: a user, a team or every user having a role in the organisation.
+// A user or a team can be set by id or by name, the name being resolved with Resolve.
+type Subject struct {
+	UserID int64 `json:"userId,omitempty"`
+	// UserLogin is the login or the email of the user
+	UserLogin string         `json:"userLogin,omitempty"`
+	TeamID    int64          `json:"teamId,omitempty"`
+	TeamName  string         `json:"teamName,omitempty"`
+	Role      types.RoleType `json:"role,omitempty"`
+}
+
+// User returns the subject of the user id
+func User(id int64) Subject {
+	return Subject{UserID: id}
+}
+
+// Team returns the subject of the team id
+func Team(id int64) Subject {
+	return Subject{TeamID: id}
+}
+
+// Role returns the subject of every user having the role in the organisation
+func Role(role types.RoleType) Subject {
+	return Subject{Role: role}
+}
+
+func (s Subject) validate() error {
+	set := 0
+	if s.UserID > 0 || len(s.UserLogin) > 0 {
+		set++
+	}
+	if s.TeamID > 0 || len(s.TeamName) > 0 {
+		set++
+	}
+	if len(s.Role) > 0 {
+		set++
+	}
+	if set != 1 {
+		return fmt.Errorf("a subject must be either a user, a team or a role")
+	}
+	return nil
+}
+
+// resolved returns true if the subject is identified by an id or a role, like in an ACL
+func (s Subject) resolved() bool {
+	return s.UserID > 0 || s.TeamID > 0 || len(s.Role) > 0
+}
+
+// key identifies the subject in an ACL. It requires a resolved subject.
+func (s Subject) key() string {
+	switch {
+	case s.UserID > 0:
+		return "user:" + strconv.FormatInt(s.UserID, 10)
+	case s.TeamID > 0:
+		return "team:" + strconv.FormatInt(s.TeamID, 10)
+	default:
+		return "role:" + string(s.Role)
+	}
+}
+
+// String returns a readable description of the subject, like team:Backend or role:Viewer
+func (s Subject) String() string {
+	switch {
+	case len(s.UserLogin) > 0:
+		return "user:" + s.UserLogin
+	case len(s.TeamName) > 0:
+		return "team:" + s.TeamName
+	}
+	return s.key()
+}
+
+// Grant is a permission granted to a subject
+type Grant struct {
+	Subject    Subject              `json:"subject"`
+	Permission types.PermissionType `json:"permission"`
+}
+
+func (g *Grant) String() string {
+	return g.Subject.String() + "=" + PermissionName(g.Permission)
+}
+
+// Set is the list of permissions wanted on a folder or a dashboard
+type Set []*Grant
+
+// ParseSet decodes a set written like "team:Backend=Edit; role:Viewer=View; user:alice=Admin".
+// The grants are separated by a semicolon or a comma. A user or a team can be given by id, like team:12.
+func ParseSet(value string) (Set, error) {
+	var set Set
+	items := strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' })
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		equal := strings.LastIndex(item, "=")
+		colon := strings.Index(item, ":")
+		if equal < 0 || colon < 0 || colon > equal {
+			return nil, fmt.Errorf("invalid permission '%s', it must be written like kind:name=permission", item)
+		}
+		permission, err := ParsePermission(strings.TrimSpace(item[equal+1:]))
+		if err != nil {
+			return nil, err
+		}
+		kind := strings.TrimSpace(item[:colon])
+		name := strings.TrimSpace(item[colon+1 : equal])
+		subject := Subject{}
+		id, err := strconv.ParseInt(name, 10, 64)
+		isID := err == nil
+		switch strings.ToLower(kind) {
+		case "user":
+			if isID {
+				subject.UserID = id
+			} else {
+				subject.UserLogin = name
+			}
+		case "team":
+			if isID {
+				subject.TeamID = id
+			} else {
+				subject.TeamName = name
+			}
+		case "role":
+			subject.Role = types.RoleType(name)
+		default:
+			return nil, fmt.Errorf("invalid subject '%s', it must be a user, a team or a role", kind)
+		}
+		set = append(set, &Grant{Subject: subject, Permission: permission})
+	}
+	return set, set.Validate()
+}
+
+// Validate checks every subject is well defined and appears only once
+func (s Set) Validate() error {
+	seen := make(map[string]bool, len(s))
+	for _, grant := range s {
+		if err := grant.Subject.validate(); err != nil {
+			return err
+		}
+		if _, exist := permissionNames[grant.Permission]; !exist {
+			return fmt.Errorf("the permission of %s is unknown", grant.Subject)
+		}
+		key := grant.Subject.String()
+		if grant.Subject.resolved() {
+			key = grant.Subject.key()
+		}
+		if seen[key] {
+			return fmt.Errorf("%s appears several times in the set", grant.Subject)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Change is the difference between the current ACL and a set
+type Change struct {
+	Added   []*Grant `json:"added,omitempty"`
+	Removed []*Grant `json:"removed,omitempty"`
+}
+
+// IsEmpty returns true if the ACL is already the one wanted
+func (c *Change) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// Diff compares the current ACL of a folder or a dashboard with the set wanted, which must be resolved.
+// The entries inherited from the parent folder are ignored since they can't be modified on the child.
+// A permission modified appears both as a removal of the old one and as an addition of the new one.
+// When keepUnlisted is true, the entries not in the set are kept instead of being removed.
+func Diff(current []*types.FolderOrDashboardPermission, desired Set, keepUnlisted bool) (*Change, error) {
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*Grant)
+	for _, grant := range Explicit(current) {
+		existing[grant.Subject.key()] = grant
+	}
+	wanted := make(map[string]*Grant, len(desired))
+	for _, grant := range desired {
+		if !grant.Subject.resolved() {
+			return nil, fmt.Errorf("the subject %s is not resolved", grant.Subject)
+		}
+		wanted[grant.Subject.key()] = grant
+	}
+
+	change := &Change{}
+	for key, grant := range wanted {
+		old, exist := existing[key]
+		if exist && old.Permission == grant.Permission {
+			continue
+		}
+		if exist {
+			change.Removed = append(change.Removed, old)
+		}
+		change.Added = append(change.Added, grant)
+	}
+	if !keepUnlisted {
+		for key, grant := range existing {
+			if _, exist := wanted[key]; !exist {
+				change.Removed = append(change.Removed, grant)
+			}
+		}
+	}
+	sortGrants(change.Added)
+	sortGrants(change.Removed)
+	return change, nil
+}
+
+// Explicit returns the entries of the ACL set on the folder or the dashboard itself, i.e. not inherited
+func Explicit(acl []*types.FolderOrDashboardPermission) Set {
+	var result Set
+	for _, entry := range acl {
+		if entry.Inherited {
+			continue
+		}
+		subject := Subject{UserID: entry.UserID, UserLogin: entry.UserLogin, TeamID: entry.TeamID, TeamName: entry.Team}
+		if entry.Role != nil {
+			subject = Role(*entry.Role)
+		}
+		result = append(result, &Grant{Subject: subject, Permission: entry.Permission})
+	}
+	return result
+}
+
+// apply returns the ACL once the change is applied on the explicit entries of current
+func apply(current []*types.FolderOrDashboardPermission, change *Change) []*types.DashboardACLUpdateItem {
+	removed := make(map[string]bool, len(change.Removed))
+	for _, grant := range change.Removed {
+		removed[grant.Subject.key()] = true
+	}
+	items := make([]*types.DashboardACLUpdateItem, 0)
+	for _, grant := range append(Explicit(current), change.Added...) {
+		if removed[grant.Subject.key()] && !contains(change.Added, grant) {
+			continue
+		}
+		item := &types.DashboardACLUpdateItem{UserID: grant.Subject.UserID, TeamID: grant.Subject.TeamID, Permission: grant.Permission}
+		if len(grant.Subject.Role) > 0 {
+			role := grant.Subject.Role
+			item.Role = &role
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func contains(grants []*Grant, grant *Grant) bool {
+	for _, g := range grants {
+		if g == grant {
+			return true
+		}
+	}
+	return false
+}
+
+func sortGrants(grants []*Grant) {
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].Subject.key() < grants[j].Subject.key()
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 Augustin Husson
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permission
+
+import (
+	"testing"
+
+	"github.com/nexucis/grafana-go-client/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func rolePtr(role types.RoleType) *types.RoleType {
+	return &role
+}
+
+func TestParseSet(t *testing.T) {
+	set, err := ParseSet("team:Backend=Edit; role:Viewer=view, user:12=Admin")
+	assert.Nil(t, err)
+	assert.Equal(t, Set{
+		{Subject: Subject{TeamName: "Backend"}, Permission: types.PermissionEdit},
+		{Subject: Role(types.RoleViewer), Permission: types.PermissionView},
+		{Subject: User(12), Permission: types.PermissionAdmin},
+	}, set)
+
+	for _, value := range []string{"team=Edit", "group:a=View", "role:Viewer=Read", "team:1=View;team:1=Edit"} {
+		_, err := ParseSet(value)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	current := []*types.FolderOrDashboardPermission{
+		{Inherited: true, Role: rolePtr(types.RoleEditor), Permission: types.PermissionEdit},
+		{Role: rolePtr(types.RoleViewer), Permission: types.PermissionView},
+		{Role: rolePtr(types.RoleEditor), Permission: types.PermissionEdit},
+		{TeamID: 3, Team: "Backend", Permission: types.PermissionView},
+		{UserID: 7, UserLogin: "alice", Permission: types.PermissionAdmin},
+	}
+	desired := Set{
+		{Subject: Role(types.RoleViewer), Permission: types.PermissionView},
+		{Subject: Team(3), Permission: types.PermissionEdit},
+		{Subject: Team(4), Permission: types.PermissionView},
+	}
+
+	change, err := Diff(current, desired, false)
+	assert.Nil(t, err)
+	var added, removed []string
+	for _, grant := range change.Added {
+		added = append(added, grant.String())
+	}
+	for _, grant := range change.Removed {
+		removed = append(removed, grant.String())
+	}
+	assert.Equal(t, []string{"team:3=Edit", "team:4=View"}, added)
+	assert.Equal(t, []string{"role:Editor=Edit", "team:Backend=View", "user:alice=Admin"}, removed)
+	assert.Equal(t, []*types.DashboardACLUpdateItem{
+		{Role: rolePtr(types.RoleViewer), Permission: types.PermissionView},
+		{TeamID: 3, Permission: types.PermissionEdit},
+		{TeamID: 4, Permission: types.PermissionView},
+	}, apply(current, change))
+
+	change, err = Diff(current, desired, true)
+	assert.Nil(t, err)
+	assert.Len(t, change.Added, 2)
+	assert.Len(t, change.Removed, 1)
+	assert.Len(t, apply(current, change), 5)
+
+	change, err = Diff(current, Explicit(current), false)
+	assert.Nil(t, err)
+	assert.True(t, change.IsEmpty())
+
+	_, err = Diff(current, Set{{Subject: Subject{TeamName: "Backend"}, Permission: types.PermissionView}}, false)
+	assert.NotNil(t, err)
+}